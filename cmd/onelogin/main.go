@@ -0,0 +1,179 @@
+// Command onelogin manages users, apps, roles and connectors of a OneLogin
+// instance.  Credentials are read from the CLIENT_ID, CLIENT_SECRET and
+// SUBDOMAIN environment variables.
+//
+//	onelogin <resource> <command> [flags] [id]
+//
+// Resources are users, apps, roles and connectors.  Commands are list,
+// get, create, update and delete, connectors only support list.  Create
+// and update read a JSON or YAML body from the file given by -f, or stdin.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghaggin/onelogin-go-client/internal/yamljson"
+	"github.com/ghaggin/onelogin-go-client/onelogin"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "onelogin:", err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: onelogin <resource> <command> [flags] [id]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "resources:", strings.Join(names, ", "))
+	fmt.Fprintln(w, "commands:  list, get, create, update, delete")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'onelogin <resource> <command> -h' for the flags of a command.")
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		usage(os.Stderr)
+		return flag.ErrHelp
+	}
+
+	r, ok := resources[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown resource %q", args[0])
+	}
+	command := args[1]
+
+	fs := flag.NewFlagSet(args[0]+" "+command, flag.ContinueOnError)
+	output := fs.String("o", "table", "output format: table, json, yaml or csv")
+
+	var exec func(c *onelogin.Client) (interface{}, error)
+	switch command {
+	case "list":
+		exec = r.list(fs)
+
+	case "get":
+		if r.get == nil {
+			return fmt.Errorf("%s does not support %s", args[0], command)
+		}
+		exec = func(c *onelogin.Client) (interface{}, error) {
+			id, err := idArg(fs)
+			if err != nil {
+				return nil, err
+			}
+			return r.get(c, id)
+		}
+
+	case "delete":
+		if r.delete == nil {
+			return fmt.Errorf("%s does not support %s", args[0], command)
+		}
+		exec = func(c *onelogin.Client) (interface{}, error) {
+			id, err := idArg(fs)
+			if err != nil {
+				return nil, err
+			}
+			return nil, r.delete(c, id)
+		}
+
+	case "create", "update":
+		if r.create == nil || r.update == nil {
+			return fmt.Errorf("%s does not support %s", args[0], command)
+		}
+		file := fs.String("f", "-", "file holding the JSON or YAML body, - for stdin")
+		exec = func(c *onelogin.Client) (interface{}, error) {
+			data, err := readBody(*file, stdin)
+			if err != nil {
+				return nil, err
+			}
+			if command == "create" {
+				return r.create(c, data)
+			}
+			id, err := idArg(fs)
+			if err != nil {
+				return nil, err
+			}
+			return r.update(c, id, data)
+		}
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if !validOutput(*output) {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close(context.Background())
+
+	result, err := exec(client)
+	if err != nil {
+		return err
+	}
+	if command == "delete" {
+		fmt.Fprintf(stdout, "deleted %s %s\n", args[0], fs.Arg(0))
+		return nil
+	}
+	return writeOutput(stdout, *output, r.columns, result)
+}
+
+func newClient() (*onelogin.Client, error) {
+	config := onelogin.ClientConfig{
+		ClientID:     os.Getenv("CLIENT_ID"),
+		ClientSecret: os.Getenv("CLIENT_SECRET"),
+		Subdomain:    os.Getenv("SUBDOMAIN"),
+	}
+	if config.ClientID == "" || config.ClientSecret == "" || config.Subdomain == "" {
+		return nil, errors.New("CLIENT_ID, CLIENT_SECRET and SUBDOMAIN must be set")
+	}
+	return onelogin.NewClient(config)
+}
+
+func idArg(fs *flag.FlagSet) (int, error) {
+	if fs.NArg() != 1 {
+		return 0, errors.New("expected a single id argument after the flags")
+	}
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", fs.Arg(0))
+	}
+	return id, nil
+}
+
+func readBody(file string, stdin io.Reader) ([]byte, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return yamljson.Convert(data)
+}
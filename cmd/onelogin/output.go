@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghaggin/onelogin-go-client/onelogin"
+	"gopkg.in/yaml.v3"
+)
+
+func validOutput(format string) bool {
+	switch format {
+	case "table", "json", "yaml", "csv":
+		return true
+	}
+	return false
+}
+
+// writeOutput prints a result or a list of results.  JSON and YAML print
+// every field, tables and CSV print the resource's columns.
+func writeOutput(w io.Writer, format string, columns []string, v interface{}) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+
+	case "yaml":
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	rows, err := toRows(v, columns)
+	if err != nil {
+		return err
+	}
+
+	if format == "csv" {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// toGeneric converts a value through JSON so that the API field names
+// and encodings are used
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	return generic, err
+}
+
+// userRow prints a user's state and status by name, the API's numeric
+// values are kept for JSON and YAML
+type userRow struct {
+	*onelogin.User
+	State  string `json:"state"`
+	Status string `json:"status"`
+}
+
+func newUserRow(user *onelogin.User) *userRow {
+	return &userRow{User: user, State: user.State.String(), Status: user.Status.String()}
+}
+
+// tableValue replaces values whose columns read better as names in
+// tables and CSV
+func tableValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *onelogin.User:
+		return newUserRow(v)
+	case []*onelogin.User:
+		rows := make([]*userRow, len(v))
+		for i, user := range v {
+			rows[i] = newUserRow(user)
+		}
+		return rows
+	}
+	return v
+}
+
+func toRows(v interface{}, columns []string) ([][]string, error) {
+	generic, err := toGeneric(tableValue(v))
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := generic.([]interface{})
+	if !ok {
+		items = []interface{}{generic}
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		object, _ := item.(map[string]interface{})
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = cell(object[column])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
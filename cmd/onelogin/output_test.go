@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ghaggin/onelogin-go-client/onelogin"
+	"github.com/stretchr/testify/require"
+)
+
+var testUsers = []*onelogin.User{
+	{ID: 1, UserName: "alice", Email: "alice@example.com", FirstName: "Alice"},
+	{ID: 2, UserName: "bob", Email: "bob@example.com", LastName: "Smith, Jr"},
+}
+
+var testColumns = []string{"id", "username", "firstname", "lastname"}
+
+func TestWriteOutput_table(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeOutput(&buf, "table", testColumns, testUsers))
+	require.Equal(t, ""+
+		"ID  USERNAME  FIRSTNAME  LASTNAME\n"+
+		"1   alice     Alice      \n"+
+		"2   bob                  Smith, Jr\n", buf.String())
+}
+
+func TestWriteOutput_csv(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeOutput(&buf, "csv", testColumns, testUsers[1]))
+	require.Equal(t, "id,username,firstname,lastname\n2,bob,,\"Smith, Jr\"\n", buf.String())
+}
+
+func TestWriteOutput_user_state(t *testing.T) {
+	users := []*onelogin.User{
+		{ID: 1, UserName: "alice", State: onelogin.UserStateApproved, Status: onelogin.UserStatusActive},
+		{ID: 2, UserName: "bob"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeOutput(&buf, "csv", []string{"id", "username", "state", "status"}, users))
+	require.Equal(t, "id,username,state,status\n1,alice,approved,active\n2,bob,unapproved,unactivated\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeOutput(&buf, "json", nil, users[0]))
+	require.Contains(t, buf.String(), `"state": 1`)
+}
+
+func TestWriteOutput_yaml(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeOutput(&buf, "yaml", testColumns, testUsers[:1]))
+	require.Equal(t, ""+
+		"- email: alice@example.com\n"+
+		"  firstname: Alice\n"+
+		"  id: 1\n"+
+		"  username: alice\n", buf.String())
+}
+
+func TestRun_errors(t *testing.T) {
+	var buf bytes.Buffer
+	require.ErrorContains(t, run([]string{"groups", "list"}, nil, &buf), `unknown resource "groups"`)
+	require.ErrorContains(t, run([]string{"users", "patch"}, nil, &buf), `unknown command "patch"`)
+	require.ErrorContains(t, run([]string{"connectors", "delete", "1"}, nil, &buf), "connectors does not support delete")
+	require.ErrorContains(t, run([]string{"users", "list", "-o", "xml"}, nil, &buf), `unknown output format "xml"`)
+	require.ErrorContains(t, run([]string{"users", "list", "-state", "bogus"}, nil, &buf), "bogus")
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghaggin/onelogin-go-client/onelogin"
+)
+
+// resource is a type of object the command manages.  list registers the
+// query flags and returns the function running the query.  Commands a
+// resource does not support are left nil.
+type resource struct {
+	columns []string
+	list    func(fs *flag.FlagSet) func(c *onelogin.Client) (interface{}, error)
+	get     func(c *onelogin.Client, id int) (interface{}, error)
+	create  func(c *onelogin.Client, data []byte) (interface{}, error)
+	update  func(c *onelogin.Client, id int, data []byte) (interface{}, error)
+	delete  func(c *onelogin.Client, id int) error
+}
+
+var resources = map[string]*resource{
+	"users": {
+		columns: []string{"id", "username", "email", "firstname", "lastname", "state", "status"},
+		list: func(fs *flag.FlagSet) func(c *onelogin.Client) (interface{}, error) {
+			query := &onelogin.UserQuery{}
+			pagingFlags(fs, &query.Paging)
+			timeFlag(fs, "created-since", &query.CreatedSince)
+			timeFlag(fs, "created-until", &query.CreatedUntil)
+			timeFlag(fs, "updated-since", &query.UpdatedSince)
+			timeFlag(fs, "updated-until", &query.UpdatedUntil)
+			timeFlag(fs, "last-login-since", &query.LastLoginSince)
+			timeFlag(fs, "last-login-until", &query.LastLoginUntil)
+			fs.StringVar(&query.FirstName, "firstname", "", "filter by first name")
+			fs.StringVar(&query.LastName, "lastname", "", "filter by last name")
+			fs.StringVar(&query.Email, "email", "", "filter by email")
+			fs.StringVar(&query.Username, "username", "", "filter by username")
+			fs.StringVar(&query.Samaccountname, "samaccountname", "", "filter by samaccountname")
+			fs.StringVar(&query.DirectoryID, "directory-id", "", "filter by directory id")
+			fs.StringVar(&query.ExternalID, "external-id", "", "filter by external id")
+			fs.StringVar(&query.AppID, "app-id", "", "filter by assigned app id")
+			fs.Func("user-ids", "comma separated user ids", func(s string) error {
+				ids, err := parseInts(s)
+				query.UserIDs = ids
+				return err
+			})
+			fs.Func("state", "filter by state, e.g. approved", func(s string) error {
+				state, err := onelogin.ParseUserState(s)
+				query.State = &state
+				return err
+			})
+			fs.Func("status", "filter by status, e.g. active", func(s string) error {
+				status, err := onelogin.ParseUserStatus(s)
+				query.Status = &status
+				return err
+			})
+			fieldsFlag(fs, &query.Fields)
+			return func(c *onelogin.Client) (interface{}, error) {
+				return c.ListUsers(query)
+			}
+		},
+		get: func(c *onelogin.Client, id int) (interface{}, error) {
+			return c.GetUser(id)
+		},
+		create: func(c *onelogin.Client, data []byte) (interface{}, error) {
+			var user onelogin.User
+			if err := json.Unmarshal(data, &user); err != nil {
+				return nil, err
+			}
+			return c.CreateUser(&user)
+		},
+		update: func(c *onelogin.Client, id int, data []byte) (interface{}, error) {
+			var user onelogin.User
+			if err := json.Unmarshal(data, &user); err != nil {
+				return nil, err
+			}
+			user.ID = id
+			return c.UpdateUser(&user)
+		},
+		delete: func(c *onelogin.Client, id int) error {
+			return c.DeleteUser(id)
+		},
+	},
+
+	"apps": {
+		columns: []string{"id", "name", "connector_id", "auth_method", "visible"},
+		list: func(fs *flag.FlagSet) func(c *onelogin.Client) (interface{}, error) {
+			query := &onelogin.AppQuery{}
+			pagingFlags(fs, &query.Paging)
+			fs.StringVar(&query.Name, "name", "", "filter by name")
+			fs.IntVar(&query.ConnectorID, "connector-id", 0, "filter by connector id")
+			authMethodFlag(fs, &query.AuthMethod)
+			return func(c *onelogin.Client) (interface{}, error) {
+				return c.ListApps(query)
+			}
+		},
+		get: func(c *onelogin.Client, id int) (interface{}, error) {
+			return c.GetApp(id)
+		},
+		create: func(c *onelogin.Client, data []byte) (interface{}, error) {
+			var app onelogin.App
+			if err := json.Unmarshal(data, &app); err != nil {
+				return nil, err
+			}
+			return c.CreateApp(&app)
+		},
+		update: func(c *onelogin.Client, id int, data []byte) (interface{}, error) {
+			// UpdateApp replaces the whole app, so the data is overlaid
+			// on the current app
+			app, err := c.GetApp(id)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, app); err != nil {
+				return nil, err
+			}
+			app.ID = id
+			if err := c.UpdateApp(app); err != nil {
+				return nil, err
+			}
+			return c.GetApp(id)
+		},
+		delete: func(c *onelogin.Client, id int) error {
+			return c.DeleteApp(id)
+		},
+	},
+
+	"roles": {
+		columns: []string{"id", "name"},
+		list: func(fs *flag.FlagSet) func(c *onelogin.Client) (interface{}, error) {
+			query := &onelogin.RoleQuery{}
+			pagingFlags(fs, &query.Paging)
+			fs.StringVar(&query.Name, "name", "", "filter by name")
+			fs.IntVar(&query.AppID, "app-id", 0, "filter by assigned app id")
+			fs.StringVar(&query.AppName, "app-name", "", "filter by assigned app name")
+			fieldsFlag(fs, &query.Fields)
+			return func(c *onelogin.Client) (interface{}, error) {
+				return c.ListRoles(query)
+			}
+		},
+		get: func(c *onelogin.Client, id int) (interface{}, error) {
+			return c.GetRole(id)
+		},
+		create: func(c *onelogin.Client, data []byte) (interface{}, error) {
+			var role onelogin.Role
+			if err := json.Unmarshal(data, &role); err != nil {
+				return nil, err
+			}
+			return c.CreateRole(&role)
+		},
+		update: func(c *onelogin.Client, id int, data []byte) (interface{}, error) {
+			// UpdateRole sets the users, admins and apps to those of the
+			// role, so the data is overlaid on the current role
+			role, err := c.GetRole(id)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, role); err != nil {
+				return nil, err
+			}
+			role.ID = id
+			return c.UpdateRole(role)
+		},
+		delete: func(c *onelogin.Client, id int) error {
+			return c.DeleteRole(id)
+		},
+	},
+
+	"connectors": {
+		columns: []string{"id", "name", "auth_method", "allows_new_parameters"},
+		list: func(fs *flag.FlagSet) func(c *onelogin.Client) (interface{}, error) {
+			query := &onelogin.AppConnectorQuery{}
+			pagingFlags(fs, &query.Paging)
+			fs.StringVar(&query.Name, "name", "", "filter by name")
+			authMethodFlag(fs, &query.AuthMethod)
+			return func(c *onelogin.Client) (interface{}, error) {
+				return c.ListConnectorIDs(query)
+			}
+		},
+	},
+}
+
+func pagingFlags(fs *flag.FlagSet, paging *onelogin.Paging) {
+	fs.IntVar(&paging.Limit, "limit", 0, "maximum number of results")
+	fs.IntVar(&paging.Page, "page", 0, "page of results")
+	fs.StringVar(&paging.Cursor, "cursor", "", "cursor of the page of results")
+}
+
+func timeFlag(fs *flag.FlagSet, name string, t *time.Time) {
+	fs.Func(name, "RFC3339 time, e.g. 2024-01-02T15:04:05Z", func(s string) error {
+		parsed, err := time.Parse(time.RFC3339, s)
+		*t = parsed
+		return err
+	})
+}
+
+func fieldsFlag(fs *flag.FlagSet, fields *[]string) {
+	fs.Func("fields", "comma separated fields to return", func(s string) error {
+		*fields = strings.Split(s, ",")
+		return nil
+	})
+}
+
+func authMethodFlag(fs *flag.FlagSet, authMethod *onelogin.AppAuthMethod) {
+	fs.Func("auth-method", "filter by auth method, e.g. saml", func(s string) error {
+		m, err := onelogin.ParseAppAuthMethod(s)
+		*authMethod = m
+		return err
+	})
+}
+
+func parseInts(s string) ([]int, error) {
+	var ints []int
+	for _, field := range strings.Split(s, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
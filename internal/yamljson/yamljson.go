@@ -0,0 +1,18 @@
+// Package yamljson converts YAML documents to JSON so that they can be
+// decoded with the json tags and custom unmarshalers of the API types
+package yamljson
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Convert converts a YAML or JSON document to JSON
+func Convert(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,13 @@
+package yamljson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	data, err := Convert([]byte("name: Staff\napps: [1, 2]\n"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name": "Staff", "apps": [1, 2]}`, string(data))
+}
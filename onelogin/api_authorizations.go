@@ -0,0 +1,284 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// APIAuthorization is an API Authorization server protecting a resource
+// https://developers.onelogin.com/api-docs/2/api-authorization/overview
+type APIAuthorization struct {
+	ID            int                            `json:"id,omitempty"`
+	Name          string                         `json:"name,omitempty"`
+	Description   string                         `json:"description,omitempty"`
+	Configuration *APIAuthorizationConfiguration `json:"configuration,omitempty"`
+}
+
+type APIAuthorizationConfiguration struct {
+	ResourceIdentifier            string   `json:"resource_identifier,omitempty"`
+	Audiences                     []string `json:"audiences,omitempty"`
+	AccessTokenExpirationMinutes  int      `json:"access_token_expiration_minutes,omitempty"`
+	RefreshTokenExpirationMinutes int      `json:"refresh_token_expiration_minutes,omitempty"`
+}
+
+type APIScope struct {
+	ID          int    `json:"id,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// APIClaim is a custom claim added to access tokens.  Claims share the
+// shape of app parameters, Name is only used on create.
+type APIClaim struct {
+	ID                       int         `json:"id,omitempty"`
+	Name                     string      `json:"name,omitempty"`
+	Label                    string      `json:"label,omitempty"`
+	UserAttributeMappings    string      `json:"user_attribute_mappings,omitempty"`
+	UserAttributeMacros      string      `json:"user_attribute_macros,omitempty"`
+	AttributeTransformations string      `json:"attribute_transformations,omitempty"`
+	SkipIfBlank              bool        `json:"skip_if_blank,omitempty"`
+	Values                   []string    `json:"values,omitempty"`
+	DefaultValues            interface{} `json:"default_values,omitempty"`
+	ProvisionedEntitlements  bool        `json:"provisioned_entitlements,omitempty"`
+}
+
+// APIClientApp is an OIDC app authorized to request tokens from an
+// API Authorization server, with the scopes it was granted
+type APIClientApp struct {
+	ID        int         `json:"client_app_id,omitempty"`
+	AppID     int         `json:"app_id,omitempty"`
+	APIAuthID int         `json:"api_auth_id,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Scopes    []*APIScope `json:"scopes,omitempty"`
+}
+
+// apiIDResponse is the body returned by the api authorization create
+// and update calls
+type apiIDResponse struct {
+	ID int `json:"id"`
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/list-authorization-servers
+func (c *Client) ListAPIAuthorizations() ([]*APIAuthorization, error) {
+	var auths []*APIAuthorization
+	err := c.exec(GET, "/api/2/api_authorizations", nil, &auths)
+	return auths, err
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/get-authorization-server
+func (c *Client) GetAPIAuthorization(id int) (*APIAuthorization, error) {
+	var auth APIAuthorization
+	err := c.exec(GET, fmt.Sprintf("/api/2/api_authorizations/%v", id), nil, &auth)
+	return &auth, err
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/create-authorization-server
+func (c *Client) CreateAPIAuthorization(auth *APIAuthorization) (*APIAuthorization, error) {
+	if auth.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+	if auth.Configuration == nil || auth.Configuration.ResourceIdentifier == "" {
+		return nil, ErrMissingField{"configuration.resource_identifier"}
+	}
+	if len(auth.Configuration.Audiences) == 0 {
+		return nil, ErrMissingField{"configuration.audiences"}
+	}
+
+	body, err := json.Marshal(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiIDResponse
+	err = c.exec(POST, "/api/2/api_authorizations", bytes.NewReader(body), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.ID = resp.ID
+	return auth, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/update-authorization-server
+func (c *Client) UpdateAPIAuthorization(auth *APIAuthorization) (*APIAuthorization, error) {
+	if auth.ID == 0 {
+		return nil, ErrMissingField{"id"}
+	}
+
+	body, err := json.Marshal(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.exec(PUT, fmt.Sprintf("/api/2/api_authorizations/%v", auth.ID), bytes.NewReader(body), nil)
+	if err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/delete-authorization-server
+func (c *Client) DeleteAPIAuthorization(id int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/api_authorizations/%v", id), nil, nil)
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/get-scopes
+func (c *Client) ListAPIScopes(authID int) ([]*APIScope, error) {
+	var scopes []*APIScope
+	err := c.exec(GET, fmt.Sprintf("/api/2/api_authorizations/%v/scopes", authID), nil, &scopes)
+	return scopes, err
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/add-scope
+func (c *Client) CreateAPIScope(authID int, scope *APIScope) (*APIScope, error) {
+	if scope.Value == "" {
+		return nil, ErrMissingField{"value"}
+	}
+
+	body, err := json.Marshal(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiIDResponse
+	err = c.exec(POST, fmt.Sprintf("/api/2/api_authorizations/%v/scopes", authID), bytes.NewReader(body), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	scope.ID = resp.ID
+	return scope, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/update-scope
+func (c *Client) UpdateAPIScope(authID int, scope *APIScope) (*APIScope, error) {
+	if scope.ID == 0 {
+		return nil, ErrMissingField{"id"}
+	}
+
+	body, err := json.Marshal(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.exec(PUT, fmt.Sprintf("/api/2/api_authorizations/%v/scopes/%v", authID, scope.ID), bytes.NewReader(body), nil)
+	if err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/delete-scope
+func (c *Client) DeleteAPIScope(authID, scopeID int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/api_authorizations/%v/scopes/%v", authID, scopeID), nil, nil)
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/get-claims
+func (c *Client) ListAPIClaims(authID int) ([]*APIClaim, error) {
+	var claims []*APIClaim
+	err := c.exec(GET, fmt.Sprintf("/api/2/api_authorizations/%v/claims", authID), nil, &claims)
+	return claims, err
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/add-claim
+func (c *Client) CreateAPIClaim(authID int, claim *APIClaim) (*APIClaim, error) {
+	if claim.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+
+	body, err := json.Marshal(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiIDResponse
+	err = c.exec(POST, fmt.Sprintf("/api/2/api_authorizations/%v/claims", authID), bytes.NewReader(body), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	claim.ID = resp.ID
+	return claim, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/update-claim
+func (c *Client) UpdateAPIClaim(authID int, claim *APIClaim) (*APIClaim, error) {
+	if claim.ID == 0 {
+		return nil, ErrMissingField{"id"}
+	}
+
+	body, err := json.Marshal(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.exec(PUT, fmt.Sprintf("/api/2/api_authorizations/%v/claims/%v", authID, claim.ID), bytes.NewReader(body), nil)
+	if err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/delete-claim
+func (c *Client) DeleteAPIClaim(authID, claimID int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/api_authorizations/%v/claims/%v", authID, claimID), nil, nil)
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/get-client-apps
+func (c *Client) ListAPIClientApps(authID int) ([]*APIClientApp, error) {
+	var clients []*APIClientApp
+	err := c.exec(GET, fmt.Sprintf("/api/2/api_authorizations/%v/clients", authID), nil, &clients)
+	return clients, err
+}
+
+// AddAPIClientApp authorizes an app against the server and grants it
+// the given scope ids
+//
+// https://developers.onelogin.com/api-docs/2/api-authorization/add-client-app
+func (c *Client) AddAPIClientApp(authID, appID int, scopeIDs []int) (*APIClientApp, error) {
+	if appID == 0 {
+		return nil, ErrMissingField{"app_id"}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"app_id": appID,
+		"scopes": nonNilInts(scopeIDs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var client APIClientApp
+	err = c.exec(POST, fmt.Sprintf("/api/2/api_authorizations/%v/clients", authID), bytes.NewReader(body), &client)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// SetAPIClientAppScopes replaces the scopes granted to a client app
+//
+// https://developers.onelogin.com/api-docs/2/api-authorization/update-client-app
+func (c *Client) SetAPIClientAppScopes(authID, clientAppID int, scopeIDs []int) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"scopes": nonNilInts(scopeIDs),
+	})
+	if err != nil {
+		return err
+	}
+	return c.exec(PUT, fmt.Sprintf("/api/2/api_authorizations/%v/clients/%v", authID, clientAppID), bytes.NewReader(body), nil)
+}
+
+// https://developers.onelogin.com/api-docs/2/api-authorization/remove-client-app
+func (c *Client) RemoveAPIClientApp(authID, clientAppID int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/api_authorizations/%v/clients/%v", authID, clientAppID), nil, nil)
+}
+
+// nonNilInts makes sure an empty list is sent as [] rather than null
+func nonNilInts(s []int) []int {
+	if s == nil {
+		return []int{}
+	}
+	return s
+}
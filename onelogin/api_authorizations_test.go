@@ -0,0 +1,60 @@
+package onelogin
+
+func (s *OneLoginTestSuite) Test_APIAuthorizationOperations() {
+	auth, err := s.client.CreateAPIAuthorization(&APIAuthorization{
+		Name:        "test-api-auth",
+		Description: "created by tests",
+		Configuration: &APIAuthorizationConfiguration{
+			ResourceIdentifier: "https://test-api.example.com",
+			Audiences:          []string{"https://test-api.example.com"},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(auth.ID)
+
+	auth.Description = "updated by tests"
+	_, err = s.client.UpdateAPIAuthorization(auth)
+	s.Require().NoError(err)
+
+	gotAuth, err := s.client.GetAPIAuthorization(auth.ID)
+	s.Require().NoError(err)
+	s.Equal("updated by tests", gotAuth.Description)
+
+	scope, err := s.client.CreateAPIScope(auth.ID, &APIScope{Value: "test:read", Description: "read"})
+	s.Require().NoError(err)
+	s.NotZero(scope.ID)
+
+	scopes, err := s.client.ListAPIScopes(auth.ID)
+	s.Require().NoError(err)
+	s.Require().Len(scopes, 1)
+	s.Equal("test:read", scopes[0].Value)
+
+	claim, err := s.client.CreateAPIClaim(auth.ID, &APIClaim{Name: "test_claim", UserAttributeMappings: "email"})
+	s.Require().NoError(err)
+	s.NotZero(claim.ID)
+
+	claims, err := s.client.ListAPIClaims(auth.ID)
+	s.Require().NoError(err)
+	s.NotEmpty(claims)
+
+	s.Require().NoError(s.client.DeleteAPIClaim(auth.ID, claim.ID))
+	s.Require().NoError(s.client.DeleteAPIScope(auth.ID, scope.ID))
+	s.Require().NoError(s.client.DeleteAPIAuthorization(auth.ID))
+}
+
+func (s *OneLoginTestSuite) Test_CreateAPIAuthorization_missing_fields() {
+	_, err := s.client.CreateAPIAuthorization(&APIAuthorization{})
+	s.Equal(ErrMissingField{"name"}, err)
+
+	_, err = s.client.CreateAPIAuthorization(&APIAuthorization{Name: "test"})
+	s.Equal(ErrMissingField{"configuration.resource_identifier"}, err)
+
+	_, err = s.client.CreateAPIAuthorization(&APIAuthorization{
+		Name:          "test",
+		Configuration: &APIAuthorizationConfiguration{ResourceIdentifier: "https://test"},
+	})
+	s.Equal(ErrMissingField{"configuration.audiences"}, err)
+
+	_, err = s.client.UpdateAPIAuthorization(&APIAuthorization{})
+	s.Equal(ErrMissingField{"id"}, err)
+}
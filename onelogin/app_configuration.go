@@ -0,0 +1,367 @@
+package onelogin
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// AppConfiguration is implemented by the typed configuration of each
+// auth method.  App.Configuration remains the flat form sent to the API,
+// App.TypedConfiguration and App.SetConfiguration convert between the two.
+type AppConfiguration interface {
+	AuthMethod() AppAuthMethod
+	toConfiguration() *Configuration
+}
+
+type SAMLSignatureAlgorithm string
+
+const (
+	SAMLSignatureAlgorithmSHA1   SAMLSignatureAlgorithm = "SHA-1"
+	SAMLSignatureAlgorithmSHA256 SAMLSignatureAlgorithm = "SHA-256"
+	SAMLSignatureAlgorithmSHA384 SAMLSignatureAlgorithm = "SHA-384"
+	SAMLSignatureAlgorithmSHA512 SAMLSignatureAlgorithm = "SHA-512"
+)
+
+// SAMLConfiguration is the configuration of a SAML app
+type SAMLConfiguration struct {
+	Audience    string
+	ConsumerURL string
+	Recipient   string
+	Validator   string
+	LoginURL    string
+	LogoutURL   string
+	RelayState  string
+
+	SignatureAlgorithm SAMLSignatureAlgorithm
+	SignElement        string
+	IssuerType         string
+	InitiaterID        string
+	EncryptionMethodID string
+
+	// nil leaves the flag unset
+	EncryptAssertion           *bool
+	GenerateAttributeValueTags *bool
+
+	// NameIDFormat is one of the NameIDFormat URNs.  A format this
+	// package does not know is kept as the API's id in NameIDFormatID.
+	NameIDFormat   string
+	NameIDFormatID string
+
+	// Assertion validity relative to the time it is issued, in minutes
+	NotValidBeforeMinutes           int
+	NotValidOnOrAfterMinutes        int
+	SessionNotValidOnOrAfterMinutes int
+
+	// Used by the AWS multi account connector
+	ProviderArn string
+	IdpList     string
+
+	// Set by some connectors, SPLoginURL is sent as login_url
+	Relay      string
+	SPLoginURL string
+}
+
+type OIDCApplicationType int
+
+const (
+	OIDCApplicationTypeWeb OIDCApplicationType = iota
+	OIDCApplicationTypeNative
+)
+
+type OIDCTokenEndpointAuthMethod int
+
+const (
+	OIDCTokenEndpointAuthMethodBasic OIDCTokenEndpointAuthMethod = iota
+	OIDCTokenEndpointAuthMethodPost
+	OIDCTokenEndpointAuthMethodNone
+)
+
+// OIDCConfiguration is the configuration of an OpenId Connect app
+type OIDCConfiguration struct {
+	RedirectURIs                  []string
+	LoginURL                      string
+	PostLogoutRedirectURI         string
+	ApplicationType               OIDCApplicationType
+	TokenEndpointAuthMethod       OIDCTokenEndpointAuthMethod
+	AccessTokenExpirationMinutes  int
+	RefreshTokenExpirationMinutes int
+}
+
+// WSFedConfiguration is the configuration of a WS-Federation app
+type WSFedConfiguration struct {
+	Realm              string
+	ReplyURL           string
+	LogoutURL          string
+	SignatureAlgorithm SAMLSignatureAlgorithm
+}
+
+func (c *SAMLConfiguration) AuthMethod() AppAuthMethod  { return AppAuthMethodSAML }
+func (c *OIDCConfiguration) AuthMethod() AppAuthMethod  { return AppAuthMethodOIDC }
+func (c *WSFedConfiguration) AuthMethod() AppAuthMethod { return AppAuthMethodWSFed }
+
+func (c *SAMLConfiguration) toConfiguration() *Configuration {
+	return &Configuration{
+		Audience:                     c.Audience,
+		ConsumerURL:                  c.ConsumerURL,
+		Recipient:                    c.Recipient,
+		Validator:                    c.Validator,
+		Login:                        c.LoginURL,
+		LogoutURL:                    c.LogoutURL,
+		RelayState:                   c.RelayState,
+		SignatureAlgorithm:           string(c.SignatureAlgorithm),
+		SAMLSignElement:              c.SignElement,
+		SAMLIssuerType:               c.IssuerType,
+		SAMLInitiaterID:              c.InitiaterID,
+		SAMLEncryptionMethodID:       c.EncryptionMethodID,
+		EncryptAssertion:             formatFlag(c.EncryptAssertion),
+		GenerateAttributeValueTags:   formatFlag(c.GenerateAttributeValueTags),
+		SAMLNameIDFormatID:           c.nameIDFormatID(),
+		SAMLNotValidBefore:           formatMinutes(c.NotValidBeforeMinutes),
+		SAMLNotValidOnOrAafter:       formatMinutes(c.NotValidOnOrAfterMinutes),
+		SAMLSessionNotValidOnOrAfter: formatMinutes(c.SessionNotValidOnOrAfterMinutes),
+		ProviderArn:                  c.ProviderArn,
+		IdpList:                      c.IdpList,
+		Relay:                        c.Relay,
+		LoginURL:                     c.SPLoginURL,
+	}
+}
+
+func (c *SAMLConfiguration) nameIDFormatID() string {
+	if id, ok := nameIDFormatIDs[c.NameIDFormat]; ok {
+		return id
+	}
+	return c.NameIDFormatID
+}
+
+func (c *OIDCConfiguration) toConfiguration() *Configuration {
+	return &Configuration{
+		RedirectURI:                   strings.Join(c.RedirectURIs, "\n"),
+		LoginURL:                      c.LoginURL,
+		PostLogoutRedirectURI:         c.PostLogoutRedirectURI,
+		OidcApplicationType:           int(c.ApplicationType),
+		TokenEndpointAuthMethod:       int(c.TokenEndpointAuthMethod),
+		AccessTokenExpirationMinutes:  c.AccessTokenExpirationMinutes,
+		RefreshTokenExpirationMinutes: c.RefreshTokenExpirationMinutes,
+	}
+}
+
+func (c *WSFedConfiguration) toConfiguration() *Configuration {
+	return &Configuration{
+		Audience:           c.Realm,
+		ConsumerURL:        c.ReplyURL,
+		LogoutURL:          c.LogoutURL,
+		SignatureAlgorithm: string(c.SignatureAlgorithm),
+	}
+}
+
+// TypedConfiguration returns the configuration typed for the app's auth
+// method: *SAMLConfiguration, *OIDCConfiguration or *WSFedConfiguration
+func (a *App) TypedConfiguration() (AppConfiguration, error) {
+	config := a.Configuration
+	if config == nil {
+		config = &Configuration{}
+	}
+
+	switch a.AuthMethod {
+	case AppAuthMethodSAML:
+		return samlConfiguration(config)
+	case AppAuthMethodOIDC:
+		return oidcConfiguration(config)
+	case AppAuthMethodWSFed:
+		return wsfedConfiguration(config)
+	default:
+		return nil, ErrInvalidConfiguration{AuthMethod: a.AuthMethod, Reason: "auth method has no typed configuration"}
+	}
+}
+
+// SetConfiguration sets the app's configuration and the matching auth method
+func (a *App) SetConfiguration(config AppConfiguration) {
+	a.AuthMethod = config.AuthMethod()
+	a.Configuration = config.toConfiguration()
+}
+
+// ValidateConfiguration checks that the flat configuration does not set
+// fields that belong to another auth method, and that the fields of the
+// app's auth method can be parsed.  Fields no typed configuration knows,
+// and enum values this package does not list, are left to the API.
+//
+// Validation only applies when AuthMethod is set.  Apps created with
+// just a ConnectorID take the connector's auth method, which is not
+// known here, so their configuration is not checked.
+func (a *App) ValidateConfiguration() error {
+	if a.Configuration == nil {
+		return nil
+	}
+	switch a.AuthMethod {
+	case AppAuthMethodSAML, AppAuthMethodOIDC, AppAuthMethodWSFed:
+	default:
+		return nil
+	}
+
+	typed, err := a.TypedConfiguration()
+	if err != nil {
+		return err
+	}
+
+	// a field set on the app that does not survive the round trip through
+	// its typed configuration, but does through another's, belongs to the
+	// other auth method
+	got := reflect.ValueOf(a.Configuration).Elem()
+	kept := reflect.ValueOf(typed.toConfiguration()).Elem()
+	var others []reflect.Value
+	for _, method := range []AppAuthMethod{AppAuthMethodSAML, AppAuthMethodOIDC, AppAuthMethodWSFed} {
+		if method == a.AuthMethod {
+			continue
+		}
+		other, err := (&App{AuthMethod: method, Configuration: a.Configuration}).TypedConfiguration()
+		if err == nil {
+			others = append(others, reflect.ValueOf(other.toConfiguration()).Elem())
+		}
+	}
+
+	for i := 0; i < got.NumField(); i++ {
+		if got.Field(i).IsZero() || !kept.Field(i).IsZero() {
+			continue
+		}
+		for _, other := range others {
+			if !other.Field(i).IsZero() {
+				return ErrInvalidConfiguration{
+					AuthMethod: a.AuthMethod,
+					Field:      configurationFieldName(got.Type().Field(i)),
+					Reason:     "not used by " + a.AuthMethod.String() + " apps",
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func samlConfiguration(c *Configuration) (*SAMLConfiguration, error) {
+	invalid := func(field, reason string) error {
+		return ErrInvalidConfiguration{AuthMethod: AppAuthMethodSAML, Field: field, Reason: reason}
+	}
+
+	config := &SAMLConfiguration{
+		Audience:           c.Audience,
+		ConsumerURL:        c.ConsumerURL,
+		Recipient:          c.Recipient,
+		Validator:          c.Validator,
+		LoginURL:           c.Login,
+		LogoutURL:          c.LogoutURL,
+		RelayState:         c.RelayState,
+		SignatureAlgorithm: SAMLSignatureAlgorithm(c.SignatureAlgorithm),
+		SignElement:        c.SAMLSignElement,
+		IssuerType:         c.SAMLIssuerType,
+		InitiaterID:        c.SAMLInitiaterID,
+		EncryptionMethodID: c.SAMLEncryptionMethodID,
+		ProviderArn:        c.ProviderArn,
+		IdpList:            c.IdpList,
+		Relay:              c.Relay,
+		SPLoginURL:         c.LoginURL,
+	}
+
+	if c.SAMLNameIDFormatID != "" {
+		for format, id := range nameIDFormatIDs {
+			if id == c.SAMLNameIDFormatID {
+				config.NameIDFormat = format
+			}
+		}
+		if config.NameIDFormat == "" {
+			config.NameIDFormatID = c.SAMLNameIDFormatID
+		}
+	}
+
+	var err error
+	if config.EncryptAssertion, err = parseFlag(c.EncryptAssertion); err != nil {
+		return nil, invalid("encrypt_assertion", err.Error())
+	}
+	if config.GenerateAttributeValueTags, err = parseFlag(c.GenerateAttributeValueTags); err != nil {
+		return nil, invalid("generate_attribute_value_tags", err.Error())
+	}
+	if config.NotValidBeforeMinutes, err = parseMinutes(c.SAMLNotValidBefore); err != nil {
+		return nil, invalid("saml_notbefore", err.Error())
+	}
+	if config.NotValidOnOrAfterMinutes, err = parseMinutes(c.SAMLNotValidOnOrAafter); err != nil {
+		return nil, invalid("saml_notonorafter", err.Error())
+	}
+	if config.SessionNotValidOnOrAfterMinutes, err = parseMinutes(c.SAMLSessionNotValidOnOrAfter); err != nil {
+		return nil, invalid("saml_sessionnotonorafter", err.Error())
+	}
+
+	return config, nil
+}
+
+func oidcConfiguration(c *Configuration) (*OIDCConfiguration, error) {
+	config := &OIDCConfiguration{
+		LoginURL:                      c.LoginURL,
+		PostLogoutRedirectURI:         c.PostLogoutRedirectURI,
+		ApplicationType:               OIDCApplicationType(c.OidcApplicationType),
+		TokenEndpointAuthMethod:       OIDCTokenEndpointAuthMethod(c.TokenEndpointAuthMethod),
+		AccessTokenExpirationMinutes:  c.AccessTokenExpirationMinutes,
+		RefreshTokenExpirationMinutes: c.RefreshTokenExpirationMinutes,
+	}
+	for _, uri := range strings.Split(c.RedirectURI, "\n") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			config.RedirectURIs = append(config.RedirectURIs, uri)
+		}
+	}
+	return config, nil
+}
+
+func wsfedConfiguration(c *Configuration) (*WSFedConfiguration, error) {
+	config := &WSFedConfiguration{
+		Realm:              c.Audience,
+		ReplyURL:           c.ConsumerURL,
+		LogoutURL:          c.LogoutURL,
+		SignatureAlgorithm: SAMLSignatureAlgorithm(c.SignatureAlgorithm),
+	}
+	return config, nil
+}
+
+// configurationFieldName returns the JSON name of a Configuration field
+func configurationFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+// parseFlag reads the "0"/"1" strings the API uses for booleans, an
+// empty string is unset
+func parseFlag(s string) (*bool, error) {
+	var b bool
+	switch s {
+	case "":
+		return nil, nil
+	case "0", "false":
+		b = false
+	case "1", "true":
+		b = true
+	default:
+		return nil, strconv.ErrSyntax
+	}
+	return &b, nil
+}
+
+func formatFlag(b *bool) string {
+	switch {
+	case b == nil:
+		return ""
+	case *b:
+		return "1"
+	}
+	return "0"
+}
+
+func parseMinutes(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func formatMinutes(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
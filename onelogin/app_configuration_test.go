@@ -0,0 +1,180 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppAuthMethod_JSON(t *testing.T) {
+	for apiValue, method := range map[int]AppAuthMethod{
+		0: AppAuthMethodPassword,
+		2: AppAuthMethodSAML,
+		6: AppAuthMethodForm,
+		7: AppAuthMethodWSFed,
+		8: AppAuthMethodOIDC,
+	} {
+		var app App
+		require.NoError(t, json.Unmarshal([]byte(`{"auth_method": `+strconv.Itoa(apiValue)+`}`), &app))
+		require.Equal(t, method, app.AuthMethod)
+		require.Equal(t, apiValue, authMethodToInt(method))
+
+		body, err := json.Marshal(&App{AuthMethod: method})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"auth_method": `+strconv.Itoa(apiValue)+`, "connector_id": 0, "name": ""}`, string(body))
+	}
+
+	body, err := json.Marshal(&App{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"connector_id": 0, "name": ""}`, string(body))
+
+	var app App
+	require.NoError(t, json.Unmarshal([]byte(`{"auth_method": "oidc"}`), &app))
+	require.Equal(t, AppAuthMethodOIDC, app.AuthMethod)
+	require.Equal(t, "oidc", app.AuthMethod.String())
+}
+
+func TestApp_TypedConfiguration(t *testing.T) {
+	encrypt := true
+	app := &App{}
+	app.SetConfiguration(&SAMLConfiguration{
+		Audience:                 "https://sp.example.com",
+		ConsumerURL:              "https://sp.example.com/acs",
+		SignatureAlgorithm:       SAMLSignatureAlgorithmSHA256,
+		EncryptAssertion:         &encrypt,
+		NameIDFormat:             NameIDFormatPersistent,
+		NotValidOnOrAfterMinutes: 3,
+	})
+	require.Equal(t, AppAuthMethodSAML, app.AuthMethod)
+	require.Equal(t, "1", app.Configuration.EncryptAssertion)
+	// unset flags are left unset
+	require.Equal(t, "", app.Configuration.GenerateAttributeValueTags)
+	require.Equal(t, "2", app.Configuration.SAMLNameIDFormatID)
+	require.Equal(t, "3", app.Configuration.SAMLNotValidOnOrAafter)
+	require.NoError(t, app.ValidateConfiguration())
+
+	typed, err := app.TypedConfiguration()
+	require.NoError(t, err)
+	saml, ok := typed.(*SAMLConfiguration)
+	require.True(t, ok)
+	require.Equal(t, &encrypt, saml.EncryptAssertion)
+	require.Nil(t, saml.GenerateAttributeValueTags)
+	require.Equal(t, NameIDFormatPersistent, saml.NameIDFormat)
+	require.Equal(t, 3, saml.NotValidOnOrAfterMinutes)
+
+	app.SetConfiguration(&OIDCConfiguration{
+		RedirectURIs:            []string{"https://a.example.com/cb", "https://b.example.com/cb"},
+		TokenEndpointAuthMethod: OIDCTokenEndpointAuthMethodPost,
+	})
+	require.Equal(t, AppAuthMethodOIDC, app.AuthMethod)
+	require.Equal(t, "https://a.example.com/cb\nhttps://b.example.com/cb", app.Configuration.RedirectURI)
+	typed, err = app.TypedConfiguration()
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://a.example.com/cb", "https://b.example.com/cb"}, typed.(*OIDCConfiguration).RedirectURIs)
+
+	_, err = (&App{AuthMethod: AppAuthMethodPassword}).TypedConfiguration()
+	require.Error(t, err)
+}
+
+func TestApp_ValidateConfiguration(t *testing.T) {
+	// SAML fields on an OIDC app
+	app := &App{
+		AuthMethod: AppAuthMethodOIDC,
+		Configuration: &Configuration{
+			RedirectURI: "https://example.com/cb",
+			ConsumerURL: "https://example.com/acs",
+		},
+	}
+	err := app.ValidateConfiguration()
+	require.Equal(t, ErrInvalidConfiguration{
+		AuthMethod: AppAuthMethodOIDC,
+		Field:      "consumer_url",
+		Reason:     "not used by oidc apps",
+	}, err)
+
+	// enum values this package does not list are left to the API
+	app = &App{
+		AuthMethod:    AppAuthMethodSAML,
+		Configuration: &Configuration{SignatureAlgorithm: "SHA-3", SAMLNameIDFormatID: "7"},
+	}
+	require.NoError(t, app.ValidateConfiguration())
+	typed, err := app.TypedConfiguration()
+	require.NoError(t, err)
+	require.Equal(t, "7", typed.(*SAMLConfiguration).NameIDFormatID)
+	app.SetConfiguration(typed)
+	require.Equal(t, "7", app.Configuration.SAMLNameIDFormatID)
+
+	app = &App{
+		AuthMethod:    AppAuthMethodSAML,
+		Configuration: &Configuration{EncryptAssertion: "yes"},
+	}
+	require.Error(t, app.ValidateConfiguration())
+
+	// no auth method, nothing to check against
+	app = &App{Configuration: &Configuration{ConsumerURL: "https://example.com/acs"}}
+	require.NoError(t, app.ValidateConfiguration())
+}
+
+func TestApp_ValidateConfiguration_samlPayload(t *testing.T) {
+	// a SAML app as returned by GetApp
+	payload := `{
+		"id": 1234,
+		"connector_id": 110016,
+		"name": "SAML Test Connector",
+		"auth_method": 2,
+		"configuration": {
+			"audience": "https://sp.example.com",
+			"consumer_url": "https://sp.example.com/acs",
+			"recipient": "https://sp.example.com/acs",
+			"validator": ".*",
+			"login": "https://sp.example.com/login",
+			"login_url": "https://sp.example.com/sso",
+			"logout_url": "https://sp.example.com/logout",
+			"relay": "https://sp.example.com/home",
+			"relaystate": "",
+			"signature_algorithm": "SHA-256",
+			"saml_sign_element": "0",
+			"saml_issuer_type": "0",
+			"saml_initiater_id": "0",
+			"saml_nameid_format_id": "0",
+			"saml_notbefore": "3",
+			"saml_notonorafter": "3",
+			"saml_sessionnotonorafter": "1440",
+			"encrypt_assertion": "0",
+			"generate_attribute_value_tags": "0"
+		}
+	}`
+	var app App
+	require.NoError(t, json.Unmarshal([]byte(payload), &app))
+	require.NoError(t, app.ValidateConfiguration())
+
+	typed, err := app.TypedConfiguration()
+	require.NoError(t, err)
+	saml := typed.(*SAMLConfiguration)
+	require.Equal(t, "https://sp.example.com/home", saml.Relay)
+	require.Equal(t, "https://sp.example.com/sso", saml.SPLoginURL)
+
+	// the fields survive the round trip back to the flat configuration
+	app.SetConfiguration(saml)
+	require.Equal(t, "https://sp.example.com/home", app.Configuration.Relay)
+	require.Equal(t, "https://sp.example.com/sso", app.Configuration.LoginURL)
+
+	// relay belongs to SAML apps
+	app = App{AuthMethod: AppAuthMethodOIDC, Configuration: &Configuration{Relay: "https://example.com"}}
+	require.Error(t, app.ValidateConfiguration())
+}
+
+func (s *OneLoginTestSuite) Test_CreateApp_invalid_configuration() {
+	_, err := s.client.CreateApp(&App{
+		ConnectorID: OIDCConnectorID,
+		Name:        "test_app",
+		AuthMethod:  AppAuthMethodOIDC,
+		Configuration: &Configuration{
+			Audience: "https://example.com",
+		},
+	})
+	s.Require().Error(err)
+	s.IsType(ErrInvalidConfiguration{}, err)
+}
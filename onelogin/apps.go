@@ -18,10 +18,10 @@ type App struct {
 	BrandID            int                   `json:"brand_id,omitempty"`
 	IconURL            string                `json:"icon_url,omitempty"`
 	Visible            bool                  `json:"visible,omitempty"`
-	AuthMethod         int                   `json:"auth_method,omitempty"`
+	AuthMethod         AppAuthMethod         `json:"auth_method,omitempty"`
 	TabID              int                   `json:"tab_id,omitempty"`
-	CreatedAt          string                `json:"created_at,omitempty"`
-	UpdatedAt          string                `json:"updated_at,omitempty"`
+	CreatedAt          *time.Time            `json:"created_at,omitempty"`
+	UpdatedAt          *time.Time            `json:"updated_at,omitempty"`
 	RoleIDs            []int                 `json:"role_ids,omitempty"`
 	AllowAssumedSignin bool                  `json:"allow_assumed_signin,omitempty"`
 	Provisioning       *Provisioning         `json:"provisioning,omitempty"`
@@ -130,6 +130,10 @@ type Resource struct {
 	ResourceID  int     `json:"resource_id,omitempty"`
 }
 
+// AppAuthMethod is the sign on method of an app.  Values are offset by
+// one from the values used by the API so that the zero value,
+// AppAuthMethodNull, can mean unset.  The JSON encoding converts to and
+// from the API values.
 type AppAuthMethod int
 
 const (
@@ -139,11 +143,69 @@ const (
 	AppAuthMethodSAML
 	AppAuthMethodAPI
 	AppAuthMethodGoogle
+	_ // 5 is not used by the API
 	AppAuthMethodForm
 	AppAuthMethodWSFed
 	AppAuthMethodOIDC
 )
 
+var appAuthMethodNames = map[AppAuthMethod]string{
+	AppAuthMethodPassword: "password",
+	AppAuthMethodOpenId:   "openid",
+	AppAuthMethodSAML:     "saml",
+	AppAuthMethodAPI:      "api",
+	AppAuthMethodGoogle:   "google",
+	AppAuthMethodForm:     "form",
+	AppAuthMethodWSFed:    "wsfed",
+	AppAuthMethodOIDC:     "oidc",
+}
+
+func (m AppAuthMethod) String() string {
+	if m == AppAuthMethodNull {
+		return "null"
+	}
+	if name, ok := appAuthMethodNames[m]; ok {
+		return name
+	}
+	return "AppAuthMethod(" + strconv.Itoa(authMethodToInt(m)) + ")"
+}
+
+func (m AppAuthMethod) MarshalJSON() ([]byte, error) {
+	if m == AppAuthMethodNull {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Itoa(authMethodToInt(m))), nil
+}
+
+// UnmarshalJSON accepts the numeric value used by the API or the name
+// returned by String
+func (m *AppAuthMethod) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*m = AppAuthMethodNull
+		return nil
+	}
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		v, err := unmarshalEnum(data, "app auth method", appAuthMethodNames)
+		if err != nil {
+			return err
+		}
+		*m = v
+		return nil
+	}
+
+	i, err := strconv.Atoi(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid app auth method: %s", string(data))
+	}
+	*m = intToAuthMethod(i)
+	return nil
+}
+
+// ParseAppAuthMethod converts a name returned by String back to an AppAuthMethod
+func ParseAppAuthMethod(name string) (AppAuthMethod, error) {
+	return parseEnum(name, "app auth method", appAuthMethodNames)
+}
+
 type AppQuery struct {
 	Paging
 	Name        string        `json:"name,omitempty"`
@@ -152,17 +214,17 @@ type AppQuery struct {
 }
 
 type AppQueryResponse struct {
-	ID                 int       `json:"id"`
-	ConnectorID        int       `json:"connector_id"`
-	Name               string    `json:"name"`
-	Description        string    `json:"description"`
-	Notes              string    `json:"notes"`
-	Visible            bool      `json:"visible"`
-	AuthMethod         int       `json:"auth_method"`
-	TabID              int       `json:"tab_id"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
-	AllowAssumedSignin bool      `json:"allow_assumed_signin"`
+	ID                 int           `json:"id"`
+	ConnectorID        int           `json:"connector_id"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	Notes              string        `json:"notes"`
+	Visible            bool          `json:"visible"`
+	AuthMethod         AppAuthMethod `json:"auth_method"`
+	TabID              int           `json:"tab_id"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+	AllowAssumedSignin bool          `json:"allow_assumed_signin"`
 }
 
 type AppConnectorQuery struct {
@@ -172,11 +234,11 @@ type AppConnectorQuery struct {
 }
 
 type AppConnectorQueryResponse struct {
-	ID                  int    `json:"id,omitempty"`
-	Name                string `json:"name,omitempty"`
-	AuthMethod          int    `json:"auth_method,omitempty"`
-	AllowsNewParameters bool   `json:"allows_new_parameters,omitempty"`
-	IconURL             string `json:"icon_url,omitempty"`
+	ID                  int           `json:"id,omitempty"`
+	Name                string        `json:"name,omitempty"`
+	AuthMethod          AppAuthMethod `json:"auth_method,omitempty"`
+	AllowsNewParameters bool          `json:"allows_new_parameters,omitempty"`
+	IconURL             string        `json:"icon_url,omitempty"`
 }
 
 func (c *Client) ListApps(query *AppQuery) ([]*AppQueryResponse, error) {
@@ -201,6 +263,10 @@ func (c *Client) GetApp(id int) (*App, error) {
 }
 
 func (c *Client) CreateApp(app *App) (*App, error) {
+	if err := app.ValidateConfiguration(); err != nil {
+		return nil, err
+	}
+
 	body, err := json.Marshal(app)
 	if err != nil {
 		return nil, err
@@ -226,6 +292,9 @@ func (c *Client) UpdateApp(app *App) error {
 	if app.ID == 0 {
 		return ErrMissingField{"id"}
 	}
+	if err := app.ValidateConfiguration(); err != nil {
+		return err
+	}
 
 	// TODO: fix delete parameters when I get a response
 	// from OneLogin reps
@@ -1,6 +1,13 @@
 package onelogin
 
-import "strconv"
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
 
 func (s *OneLoginTestSuite) Test_ListConnectorIDs() {
 	connectors, err := s.client.ListConnectorIDs(&AppConnectorQuery{
@@ -178,3 +185,12 @@ func (s *OneLoginTestSuite) Test_deleteAppParameters() {
 	s.Require().NotNil(err)
 	s.Equal(ErrOneloginAPIBroken{}, err)
 }
+
+func TestApp_timestamps(t *testing.T) {
+	var app App
+	err := json.Unmarshal([]byte(`{"id": 1, "created_at": "2023-01-02T03:04:05Z", "updated_at": null}`), &app)
+	require.NoError(t, err)
+	require.NotNil(t, app.CreatedAt)
+	require.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), app.CreatedAt.UTC())
+	require.Nil(t, app.UpdatedAt)
+}
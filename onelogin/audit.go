@@ -0,0 +1,302 @@
+package onelogin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditOutcome is the result of an audited request
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+	AuditDryRun  AuditOutcome = "dry_run"
+)
+
+// AuditRecord describes a mutating request made by the client.  The
+// resource type is the path without its ids, e.g. roles/users for
+// /api/2/roles/5/users, and the resource id is the last id in the path.
+// For a POST that creates a resource the resource id is the id in the
+// response.  Before is the state of the resource, or of the resource a
+// sub-resource belongs to, before the request.  Secrets in Body and
+// Before are redacted.
+type AuditRecord struct {
+	Time         time.Time       `json:"time"`
+	Actor        string          `json:"actor,omitempty"`
+	Reason       string          `json:"reason,omitempty"`
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id,omitempty"`
+	Body         json.RawMessage `json:"body,omitempty"`
+	Before       json.RawMessage `json:"before,omitempty"`
+	Outcome      AuditOutcome    `json:"outcome"`
+	StatusCode   int             `json:"status_code,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// AuditSink stores audit records
+type AuditSink interface {
+	WriteAudit(record *AuditRecord) error
+}
+
+type auditContextKey struct{}
+
+type auditActor struct {
+	actor  string
+	reason string
+}
+
+// WithAuditActor returns a context whose requests are attributed to actor
+// for reason in audit records.  Use it with Client.WithContext.
+func WithAuditActor(ctx context.Context, actor, reason string) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, auditActor{actor: actor, reason: reason})
+}
+
+// MemoryAuditSink keeps audit records in memory
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	records []*AuditRecord
+}
+
+func (s *MemoryAuditSink) WriteAudit(record *AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// Records returns the records written so far
+func (s *MemoryAuditSink) Records() []*AuditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]*AuditRecord, len(s.records))
+	copy(records, s.records)
+	return records
+}
+
+// FileAuditSink appends audit records to a file as JSON lines
+type FileAuditSink struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileAuditSink) WriteAudit(record *AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditRequest sends a mutating request and writes its audit record.  An
+// error writing the record is returned when the request itself succeeded.
+func (c *Client) auditRequest(ctx context.Context, req *oneloginRequest) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		body, err = io.ReadAll(req.body)
+		if err != nil {
+			return err
+		}
+		req.body = bytes.NewReader(body)
+	}
+
+	record := &AuditRecord{
+		Time:   time.Now().UTC(),
+		Method: string(req.method),
+		Path:   req.path,
+		Body:   redactJSON(body),
+	}
+	record.ResourceType, record.ResourceID = auditResource(req.path)
+	if actor, ok := ctx.Value(auditContextKey{}).(auditActor); ok {
+		record.Actor = actor.actor
+		record.Reason = actor.reason
+	}
+
+	// creates have no id yet, POSTs to a sub-resource such as the users of
+	// a role fetch the role
+	if c.config.AuditBeforeState && record.ResourceID != "" && !(c.config.DryRun && record.ResourceID == "0") {
+		var before json.RawMessage
+		err := c.sendRequest(ctx, &oneloginRequest{
+			method:    GET,
+			path:      auditResourcePath(req.path),
+			respModel: &before,
+		})
+		if err == nil {
+			record.Before = redactJSON(before)
+		}
+	}
+
+	err := c.sendRequest(ctx, req)
+	switch {
+	case err != nil:
+		record.Outcome = AuditFailure
+		record.Error = err.Error()
+		var reqErr ErrRequestFailed
+		if errors.As(err, &reqErr) {
+			// the response body is redacted like the request body
+			record.StatusCode = reqErr.StatusCode
+			record.Error = fmt.Sprintf("request failed with status code %d", reqErr.StatusCode)
+			if body := redactJSON(reqErr.Body); body != nil {
+				record.Error += ": " + string(body)
+			}
+		}
+	case c.config.DryRun:
+		record.Outcome = AuditDryRun
+	default:
+		record.Outcome = AuditSuccess
+		if req.method == POST && !isAuditID(lastPathSegment(req.path)) {
+			if id := responseID(req.respModel); id != "" && id != "0" {
+				record.ResourceID = id
+			}
+		}
+	}
+
+	if auditErr := c.config.AuditSink.WriteAudit(record); auditErr != nil && err == nil {
+		return fmt.Errorf("failed to write audit record: %w", auditErr)
+	}
+	return err
+}
+
+// auditResource splits a path into the resource type and the last id
+func auditResource(path string) (string, string) {
+	var types []string
+	var id string
+	for i, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		// skip the api prefix and version
+		if i < 2 && (segment == "api" || isAuditID(segment)) {
+			continue
+		}
+		if isAuditID(segment) {
+			id = segment
+			continue
+		}
+		types = append(types, segment)
+	}
+	return strings.Join(types, "/"), id
+}
+
+// auditResourcePath trims a path after its last id, giving the path of
+// the resource a sub-resource belongs to
+func auditResourcePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i > 2; i-- {
+		if isAuditID(segments[i]) {
+			return strings.Join(segments[:i+1], "/")
+		}
+	}
+	return path
+}
+
+func lastPathSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// responseID reads the id of a decoded response, either an object with
+// an id or a v1 envelope holding one
+func responseID(respModel interface{}) string {
+	if respModel == nil {
+		return ""
+	}
+	data, err := json.Marshal(respModel)
+	if err != nil {
+		return ""
+	}
+	var resp struct {
+		ID   json.Number `json:"id"`
+		Data []struct {
+			ID json.Number `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return ""
+	}
+	if resp.ID == "" && len(resp.Data) == 1 {
+		return resp.Data[0].ID.String()
+	}
+	return resp.ID.String()
+}
+
+func isAuditID(segment string) bool {
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
+// redactedFields are the fields whose values are replaced in audit records
+var redactedFields = map[string]bool{
+	"password":              true,
+	"password_confirmation": true,
+	"salt":                  true,
+	"client_secret":         true,
+	"secret":                true,
+	"token":                 true,
+	"access_token":          true,
+	"refresh_token":         true,
+	"otp_token":             true,
+	"state_token":           true,
+	"session_token":         true,
+	"private_key":           true,
+	"api_key":               true,
+}
+
+const redacted = "[REDACTED]"
+
+// redactJSON replaces the values of secret fields at any depth.  Bodies
+// that are not JSON are dropped.
+func redactJSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactedFields[strings.ToLower(key)] && value != nil {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,146 @@
+package onelogin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRequest_dry_run(t *testing.T) {
+	sink := &MemoryAuditSink{}
+	c := &Client{config: ClientConfig{DryRun: true, AuditSink: sink}}
+
+	ctx := WithAuditActor(context.Background(), "alice", "JIRA-123")
+	_, err := c.WithContext(ctx).CreateUser(&User{UserName: "bob", Email: "bob@example.com", Password: "hunter2"})
+	require.NoError(t, err)
+	require.NoError(t, c.DeleteRole(5))
+
+	records := sink.Records()
+	require.Len(t, records, 2)
+
+	require.Equal(t, "alice", records[0].Actor)
+	require.Equal(t, "JIRA-123", records[0].Reason)
+	require.Equal(t, "POST", records[0].Method)
+	require.Equal(t, "users", records[0].ResourceType)
+	require.Equal(t, "", records[0].ResourceID)
+	require.Equal(t, AuditDryRun, records[0].Outcome)
+	require.JSONEq(t, `{"username": "bob", "email": "bob@example.com", "password": "[REDACTED]"}`, string(records[0].Body))
+
+	require.Equal(t, "", records[1].Actor)
+	require.Equal(t, "roles", records[1].ResourceType)
+	require.Equal(t, "5", records[1].ResourceID)
+	require.Nil(t, records[1].Body)
+
+	// the unredacted body is still sent
+	require.JSONEq(t, `{"username": "bob", "email": "bob@example.com", "password": "hunter2"}`, string(c.DryRunRequests()[0].Body))
+}
+
+func TestAuditRequest_ids_and_before_state(t *testing.T) {
+	var gets []string
+	sink := &MemoryAuditSink{}
+	c := newTestClient(ClientConfig{AuditSink: sink, AuditBeforeState: true}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			gets = append(gets, r.URL.Path)
+			w.Write([]byte(`{"id": 5, "name": "Staff", "users": [1]}`))
+		case r.URL.Path == "/api/2/roles":
+			w.Write([]byte(`{"id": 5}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+
+	role, err := c.CreateRole(&Role{Name: "Staff"})
+	require.NoError(t, err)
+	require.NoError(t, c.addRoleUsers(role.ID, []int{2}))
+
+	records := sink.Records()
+	require.Len(t, records, 2)
+
+	// the create has no state before it and records the new id
+	require.Equal(t, "roles", records[0].ResourceType)
+	require.Equal(t, "5", records[0].ResourceID)
+	require.Nil(t, records[0].Before)
+
+	// adding users records the role they are added to
+	require.Equal(t, "roles/users", records[1].ResourceType)
+	require.Equal(t, "5", records[1].ResourceID)
+	require.JSONEq(t, `{"id": 5, "name": "Staff", "users": [1]}`, string(records[1].Before))
+	require.Equal(t, []string{"/api/2/roles/5"}, gets)
+}
+
+func TestAuditResource(t *testing.T) {
+	for path, want := range map[string][2]string{
+		"/api/2/users":                   {"users", ""},
+		"/api/2/users/12":                {"users", "12"},
+		"/api/2/roles/5/users":           {"roles/users", "5"},
+		"/api/2/apps/7/rules/9":          {"apps/rules", "9"},
+		"/api/2/users/custom_attributes": {"users/custom_attributes", ""},
+		"/api/1/invites/get_invite_link": {"invites/get_invite_link", ""},
+	} {
+		resourceType, id := auditResource(path)
+		require.Equal(t, want, [2]string{resourceType, id}, path)
+	}
+
+	require.Equal(t, "/api/2/roles/5", auditResourcePath("/api/2/roles/5/users"))
+	require.Equal(t, "/api/2/apps/7/rules/9", auditResourcePath("/api/2/apps/7/rules/9"))
+	require.Equal(t, "/api/2/users", auditResourcePath("/api/2/users"))
+}
+
+func TestRedactJSON(t *testing.T) {
+	require.JSONEq(t, `{
+		"configuration": {"token_endpoint_auth_method": 1},
+		"sso": {"client_id": "id", "client_secret": "[REDACTED]"},
+		"users": [{"password": "[REDACTED]", "salt": null}]
+	}`, string(redactJSON([]byte(`{
+		"configuration": {"token_endpoint_auth_method": 1},
+		"sso": {"client_id": "id", "client_secret": "s3cret"},
+		"users": [{"password": "hunter2", "salt": null}]
+	}`))))
+
+	require.Nil(t, redactJSON(nil))
+	require.Nil(t, redactJSON([]byte("not json")))
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink := &FileAuditSink{Path: path}
+	require.NoError(t, sink.WriteAudit(&AuditRecord{Method: "POST", Path: "/api/2/users", Outcome: AuditSuccess}))
+	require.NoError(t, sink.WriteAudit(&AuditRecord{Method: "DELETE", Path: "/api/2/users/1", Outcome: AuditFailure, StatusCode: 404}))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []*AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, &record)
+	}
+	require.Len(t, records, 2)
+	require.Equal(t, 404, records[1].StatusCode)
+}
+
+func TestClient_WithContext(t *testing.T) {
+	c := &Client{config: ClientConfig{DryRun: true}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	derived := c.WithContext(ctx)
+	require.Equal(t, ctx, derived.context())
+	require.Equal(t, context.Background(), c.context())
+
+	// recorded requests and the closed state are shared
+	require.NoError(t, derived.DeleteRole(1))
+	require.Len(t, c.DryRunRequests(), 1)
+	require.NoError(t, c.Close(context.Background()))
+	require.Equal(t, ErrClientClosed{}, derived.DeleteRole(1))
+}
@@ -0,0 +1,186 @@
+package onelogin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBatchConcurrency = 4
+
+	// DefaultRateLimitHeadroom is the number of requests left in the rate
+	// limit window at which batches pause until the window resets
+	DefaultRateLimitHeadroom = 10
+)
+
+// RateLimit is the state of the rate limit reported by the last response
+// https://developers.onelogin.com/api-docs/2/getting-started/rate-limits
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimit returns the rate limit reported by the last response, false
+// if no response has reported one yet
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c = c.root()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rateLimit == nil {
+		return RateLimit{}, false
+	}
+	return *c.rateLimit, true
+}
+
+func (c *Client) updateRateLimit(header http.Header) {
+	rateLimit, ok := parseRateLimit(header, time.Now())
+	if !ok {
+		return
+	}
+
+	c = c.root()
+	c.mu.Lock()
+	c.rateLimit = &rateLimit
+	c.mu.Unlock()
+}
+
+// parseRateLimit reads the rate limit headers, the reset header is the
+// number of seconds until the window resets
+func parseRateLimit(header http.Header, now time.Time) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	reset, err := strconv.Atoi(header.Get("X-RateLimit-Reset"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	return RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     now.Add(time.Duration(reset) * time.Second),
+	}, true
+}
+
+// waitForRateLimit blocks until more than headroom requests are left in
+// the rate limit window or the window has reset
+func (c *Client) waitForRateLimit(ctx context.Context, headroom int) error {
+	rateLimit, ok := c.RateLimit()
+	if !ok || rateLimit.Remaining > headroom {
+		return nil
+	}
+
+	wait := time.Until(rateLimit.Reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// BatchConfig controls how a batch is run.  The zero value uses
+// DefaultBatchConcurrency and DefaultRateLimitHeadroom.
+type BatchConfig struct {
+	Concurrency int
+
+	// RateLimitHeadroom is the number of requests left in the rate limit
+	// window at which the batch pauses until the window resets.  It
+	// should be at least Concurrency since running items are not paused.
+	RateLimitHeadroom int
+}
+
+// BatchOp is an item of a batch.  It is called with a client bound to
+// the batch's context.
+type BatchOp[T any] func(c *Client) (T, error)
+
+// BatchResult is the outcome of a batch item.  Index is the position of
+// the item.  Err is the error returned by the item, or ErrBatchSkipped if
+// the item was not run.
+type BatchResult[T any] struct {
+	Index int
+	Value T
+	Err   error
+}
+
+// Batch runs ops concurrently and returns a result for each, in the
+// order of ops.  Failed items do not stop the batch.  Once ctx is done
+// no more items are started and the remaining ones are returned as
+// skipped.
+func (c *Client) Batch(ctx context.Context, config *BatchConfig, ops ...BatchOp[any]) []BatchResult[any] {
+	return RunBatch(ctx, c, config, ops)
+}
+
+// RunBatch is Batch with typed results
+func RunBatch[T any](ctx context.Context, c *Client, config *BatchConfig, ops []BatchOp[T]) []BatchResult[T] {
+	if config == nil {
+		config = &BatchConfig{}
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	headroom := config.RateLimitHeadroom
+	if headroom <= 0 {
+		headroom = DefaultRateLimitHeadroom
+	}
+
+	results := make([]BatchResult[T], len(ops))
+	for i := range results {
+		results[i].Index = i
+	}
+
+	client := c.WithContext(ctx)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(ops); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := client.waitForRateLimit(ctx, headroom); err != nil {
+					results[i].Err = ErrBatchSkipped{Cause: err}
+					continue
+				}
+				if err := ctx.Err(); err != nil {
+					results[i].Err = ErrBatchSkipped{Cause: err}
+					continue
+				}
+				results[i].Value, results[i].Err = ops[i](client)
+			}
+		}()
+	}
+
+	next := 0
+	for ; next < len(ops); next++ {
+		select {
+		case indexes <- next:
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := next; i < len(ops); i++ {
+		results[i].Err = ErrBatchSkipped{Cause: ctx.Err()}
+	}
+	return results
+}
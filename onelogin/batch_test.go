@@ -0,0 +1,147 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_Batch() {
+	users, err := s.client.ListUsers(&UserQuery{Paging: Paging{Limit: 3}})
+	s.Require().NoError(err)
+
+	ids := []int{}
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	ids = append(ids, -1)
+
+	ops := make([]BatchOp[*User], len(ids))
+	for i, id := range ids {
+		id := id
+		ops[i] = func(c *Client) (*User, error) {
+			return c.GetUser(id)
+		}
+	}
+
+	results := RunBatch(context.Background(), s.client, nil, ops)
+	s.Require().Len(results, len(ids))
+	for i, result := range results[:len(users)] {
+		s.Require().NoError(result.Err)
+		s.Equal(ids[i], result.Value.ID)
+	}
+
+	var reqErr ErrRequestFailed
+	s.Require().ErrorAs(results[len(users)].Err, &reqErr)
+	s.Equal(http.StatusNotFound, reqErr.StatusCode)
+
+	_, ok := s.client.RateLimit()
+	s.True(ok)
+}
+
+func TestRunBatch(t *testing.T) {
+	var active, maxActive int32
+	ops := make([]BatchOp[int], 20)
+	for i := range ops {
+		i := i
+		ops[i] = func(c *Client) (int, error) {
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			for {
+				m := atomic.LoadInt32(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+
+			if i%5 == 0 {
+				return 0, ErrMissingField{"id"}
+			}
+			return i * 2, nil
+		}
+	}
+
+	results := RunBatch(context.Background(), &Client{}, &BatchConfig{Concurrency: 3}, ops)
+	require.Len(t, results, 20)
+	require.LessOrEqual(t, maxActive, int32(3))
+	for i, result := range results {
+		require.Equal(t, i, result.Index)
+		if i%5 == 0 {
+			require.Equal(t, ErrMissingField{"id"}, result.Err)
+		} else {
+			require.NoError(t, result.Err)
+			require.Equal(t, i*2, result.Value)
+		}
+	}
+}
+
+func TestRunBatch_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ops := make([]BatchOp[any], 10)
+	for i := range ops {
+		i := i
+		ops[i] = func(c *Client) (any, error) {
+			if i == 1 {
+				cancel()
+			}
+			return i, nil
+		}
+	}
+
+	results := (&Client{}).Batch(ctx, &BatchConfig{Concurrency: 1}, ops...)
+	require.Len(t, results, 10)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+
+	skipped := 0
+	for _, result := range results[2:] {
+		if result.Err != nil {
+			require.True(t, errors.Is(result.Err, context.Canceled))
+			require.ErrorAs(t, result.Err, &ErrBatchSkipped{})
+			skipped++
+		}
+	}
+	// at most the item handed to the worker before the cancel was seen runs
+	require.GreaterOrEqual(t, skipped, 7)
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Now()
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", "4998")
+	header.Set("X-RateLimit-Reset", "60")
+
+	rateLimit, ok := parseRateLimit(header, now)
+	require.True(t, ok)
+	require.Equal(t, RateLimit{Limit: 5000, Remaining: 4998, Reset: now.Add(time.Minute)}, rateLimit)
+
+	_, ok = parseRateLimit(http.Header{}, now)
+	require.False(t, ok)
+}
+
+func TestWaitForRateLimit(t *testing.T) {
+	c := &Client{}
+	require.NoError(t, c.waitForRateLimit(context.Background(), 10))
+
+	c.rateLimit = &RateLimit{Limit: 100, Remaining: 5, Reset: time.Now().Add(50 * time.Millisecond)}
+	start := time.Now()
+	require.NoError(t, c.WithContext(context.Background()).waitForRateLimit(context.Background(), 10))
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	// enough headroom, no wait
+	c.rateLimit = &RateLimit{Limit: 100, Remaining: 50, Reset: time.Now().Add(time.Hour)}
+	require.NoError(t, c.waitForRateLimit(context.Background(), 10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.rateLimit.Remaining = 0
+	require.ErrorIs(t, c.waitForRateLimit(ctx, 10), context.Canceled)
+}
@@ -0,0 +1,233 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	urlpkg "net/url"
+)
+
+// Brand customizes the login experience for the apps assigned to it
+// https://developers.onelogin.com/api-docs/2/branding/overview
+type Brand struct {
+	ID                              int         `json:"id,omitempty"`
+	Name                            string      `json:"name,omitempty"`
+	Enabled                         *bool       `json:"enabled,omitempty"`
+	CustomSupportEnabled            *bool       `json:"custom_support_enabled,omitempty"`
+	CustomColor                     string      `json:"custom_color,omitempty"`
+	CustomAccentColor               string      `json:"custom_accent_color,omitempty"`
+	CustomMaskingColor              string      `json:"custom_masking_color,omitempty"`
+	CustomMaskingOpacity            int         `json:"custom_masking_opacity,omitempty"`
+	EnableCustomLabelForLoginScreen *bool       `json:"enable_custom_label_for_login_screen,omitempty"`
+	CustomLabelTextForLoginScreen   string      `json:"custom_label_text_for_login_screen,omitempty"`
+	LoginInstructionTitle           string      `json:"login_instruction_title,omitempty"`
+	LoginInstruction                string      `json:"login_instruction,omitempty"`
+	HideOneLoginFooter              *bool       `json:"hide_onelogin_footer,omitempty"`
+	MFAEnrollmentMessage            string      `json:"mfa_enrollment_message,omitempty"`
+	Background                      *BrandImage `json:"background,omitempty"`
+	Logo                            *BrandImage `json:"logo,omitempty"`
+}
+
+// BrandImage is a brand's logo or background.  Images are uploaded by
+// setting Data to the base64 encoded file, the other fields are read only.
+type BrandImage struct {
+	OriginalURL string `json:"original_url,omitempty"`
+	FileSize    int    `json:"file_size,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Data        string `json:"data,omitempty"`
+}
+
+// MessageTemplate is an email or SMS message sent to users of a brand,
+// one template exists per type and locale
+type MessageTemplate struct {
+	ID        int                     `json:"id,omitempty"`
+	AccountID int                     `json:"account_id,omitempty"`
+	Type      string                  `json:"type,omitempty"`
+	Locale    string                  `json:"locale,omitempty"`
+	Template  *MessageTemplateContent `json:"template,omitempty"`
+}
+
+// MessageTemplateContent holds the message body.  SMS templates only
+// use Message, email templates use Subject, HTML and Plain.
+type MessageTemplateContent struct {
+	Subject string `json:"subject,omitempty"`
+	HTML    string `json:"html,omitempty"`
+	Plain   string `json:"plain,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/list-brands
+func (c *Client) ListBrands(paging *Paging) ([]*Brand, error) {
+	var brands []*Brand
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        "/api/2/branding/brands",
+		queryParams: addPagingParams(map[string]string{}, paging),
+		respModel:   &brands,
+	})
+	return brands, err
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/get-brand
+func (c *Client) GetBrand(id int) (*Brand, error) {
+	var brand Brand
+	err := c.exec(GET, fmt.Sprintf("/api/2/branding/brands/%v", id), nil, &brand)
+	return &brand, err
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/create-brand
+func (c *Client) CreateBrand(brand *Brand) (*Brand, error) {
+	if brand.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+
+	body, err := json.Marshal(brand)
+	if err != nil {
+		return nil, err
+	}
+
+	var newBrand Brand
+	err = c.exec(POST, "/api/2/branding/brands", bytes.NewReader(body), &newBrand)
+	if err != nil {
+		return nil, err
+	}
+	return &newBrand, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/update-brand
+func (c *Client) UpdateBrand(brand *Brand) (*Brand, error) {
+	if brand.ID == 0 {
+		return nil, ErrMissingField{"id"}
+	}
+
+	body, err := json.Marshal(brand)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedBrand Brand
+	err = c.exec(PUT, fmt.Sprintf("/api/2/branding/brands/%v", brand.ID), bytes.NewReader(body), &updatedBrand)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedBrand, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/delete-brand
+func (c *Client) DeleteBrand(id int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/branding/brands/%v", id), nil, nil)
+}
+
+// ListBrandApps lists the apps assigned to a brand
+//
+// https://developers.onelogin.com/api-docs/2/branding/list-brand-apps
+func (c *Client) ListBrandApps(brandID int, paging *Paging) ([]*AppQueryResponse, error) {
+	var apps []*AppQueryResponse
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        fmt.Sprintf("/api/2/branding/brands/%v/apps", brandID),
+		queryParams: addPagingParams(map[string]string{}, paging),
+		respModel:   &apps,
+	})
+	return apps, err
+}
+
+// SetAppBrand assigns an app to a brand, a brand id of 0 returns the
+// app to the account's default branding
+func (c *Client) SetAppBrand(appID, brandID int) error {
+	if appID == 0 {
+		return ErrMissingField{"id"}
+	}
+
+	var brand interface{}
+	if brandID != 0 {
+		brand = brandID
+	}
+	body, err := json.Marshal(map[string]interface{}{"brand_id": brand})
+	if err != nil {
+		return err
+	}
+
+	return c.exec(PUT, fmt.Sprintf("/api/2/apps/%v", appID), bytes.NewReader(body), nil)
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/list-message-templates
+func (c *Client) ListMessageTemplates(brandID int, locale string) ([]*MessageTemplate, error) {
+	params := map[string]string{}
+	if locale != "" {
+		params["locale"] = locale
+	}
+
+	var templates []*MessageTemplate
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        fmt.Sprintf("/api/2/branding/brands/%v/templates", brandID),
+		queryParams: params,
+		respModel:   &templates,
+	})
+	return templates, err
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/get-message-template
+func (c *Client) GetMessageTemplate(brandID, templateID int) (*MessageTemplate, error) {
+	var template MessageTemplate
+	err := c.exec(GET, fmt.Sprintf("/api/2/branding/brands/%v/templates/%v", brandID, templateID), nil, &template)
+	return &template, err
+}
+
+// GetMessageTemplateByType returns a brand's template of a type in a locale
+//
+// https://developers.onelogin.com/api-docs/2/branding/get-template-by-type
+func (c *Client) GetMessageTemplateByType(brandID int, templateType, locale string) (*MessageTemplate, error) {
+	var template MessageTemplate
+	path := fmt.Sprintf("/api/2/branding/brands/%v/templates/%s/%s",
+		brandID, urlpkg.PathEscape(templateType), urlpkg.PathEscape(locale))
+	err := c.exec(GET, path, nil, &template)
+	return &template, err
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/create-message-template
+func (c *Client) CreateMessageTemplate(brandID int, template *MessageTemplate) (*MessageTemplate, error) {
+	if template.Type == "" {
+		return nil, ErrMissingField{"type"}
+	}
+	if template.Locale == "" {
+		return nil, ErrMissingField{"locale"}
+	}
+
+	body, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var newTemplate MessageTemplate
+	err = c.exec(POST, fmt.Sprintf("/api/2/branding/brands/%v/templates", brandID), bytes.NewReader(body), &newTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &newTemplate, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/update-message-template
+func (c *Client) UpdateMessageTemplate(brandID int, template *MessageTemplate) (*MessageTemplate, error) {
+	if template.ID == 0 {
+		return nil, ErrMissingField{"id"}
+	}
+
+	body, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedTemplate MessageTemplate
+	err = c.exec(PUT, fmt.Sprintf("/api/2/branding/brands/%v/templates/%v", brandID, template.ID), bytes.NewReader(body), &updatedTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedTemplate, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/branding/delete-message-template
+func (c *Client) DeleteMessageTemplate(brandID, templateID int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/branding/brands/%v/templates/%v", brandID, templateID), nil, nil)
+}
@@ -0,0 +1,46 @@
+package onelogin
+
+func (s *OneLoginTestSuite) Test_BrandOperations() {
+	enabled := false
+	brand, err := s.client.CreateBrand(&Brand{
+		Name:             "test-brand",
+		Enabled:          &enabled,
+		CustomColor:      "#000000",
+		LoginInstruction: "created by tests",
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(brand.ID)
+	s.Equal("test-brand", brand.Name)
+
+	brand.LoginInstruction = "updated by tests"
+	brand, err = s.client.UpdateBrand(brand)
+	s.Require().NoError(err)
+	s.Equal("updated by tests", brand.LoginInstruction)
+
+	gotBrand, err := s.client.GetBrand(brand.ID)
+	s.Require().NoError(err)
+	s.Equal(brand.ID, gotBrand.ID)
+
+	brands, err := s.client.ListBrands(&Paging{})
+	s.Require().NoError(err)
+	s.NotEmpty(brands)
+
+	_, err = s.client.ListMessageTemplates(brand.ID, "en")
+	s.Require().NoError(err)
+
+	err = s.client.DeleteBrand(brand.ID)
+	s.NoError(err)
+}
+
+func (s *OneLoginTestSuite) Test_Brand_missing_fields() {
+	_, err := s.client.CreateBrand(&Brand{})
+	s.Equal(ErrMissingField{"name"}, err)
+
+	_, err = s.client.UpdateBrand(&Brand{})
+	s.Equal(ErrMissingField{"id"}, err)
+
+	_, err = s.client.CreateMessageTemplate(1, &MessageTemplate{Type: "email_forgot_password"})
+	s.Equal(ErrMissingField{"locale"}, err)
+
+	s.Equal(ErrMissingField{"id"}, s.client.SetAppBrand(0, 1))
+}
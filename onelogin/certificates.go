@@ -0,0 +1,109 @@
+package onelogin
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExpiringCertificate is an app whose signing certificate expires
+// inside the window passed to ListExpiringAppCertificates.  Err is set
+// when the certificate could not be parsed, NotAfter is then zero.
+type ExpiringCertificate struct {
+	AppID       int
+	AppName     string
+	Certificate *Certificate
+	NotAfter    time.Time
+	Err         error
+}
+
+// Parse decodes the certificate value, which may be PEM or bare
+// base64 DER as found in SAML metadata
+func (c *Certificate) Parse() (*x509.Certificate, error) {
+	if block, _ := pem.Decode([]byte(c.Value)); block != nil {
+		return x509.ParseCertificate(block.Bytes)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(c.Value), ""))
+	if err != nil {
+		return nil, fmt.Errorf("certificate %v is neither PEM nor base64 DER", c.ID)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// ExpiresAt returns the certificate's NotAfter time
+func (c *Certificate) ExpiresAt() (time.Time, error) {
+	cert, err := c.Parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// RotateAppCertificate switches an app onto another account
+// certificate.  The app is read and written back whole, since UpdateApp
+// replaces the app.
+func (c *Client) RotateAppCertificate(appID, certificateID int) error {
+	if appID == 0 {
+		return ErrMissingField{"id"}
+	}
+	if certificateID == 0 {
+		return ErrMissingField{"certificate.id"}
+	}
+
+	app, err := c.GetApp(appID)
+	if err != nil {
+		return err
+	}
+	if app.SSO == nil {
+		app.SSO = &SSO{}
+	}
+	app.SSO.Certificate = &Certificate{ID: certificateID}
+	return c.UpdateApp(app)
+}
+
+// ListExpiringAppCertificates returns every SAML app whose signing
+// certificate has expired or expires within the given window, soonest
+// first.  Apps whose certificate cannot be parsed are returned first
+// with Err set, so that one bad certificate does not hide the others.
+func (c *Client) ListExpiringAppCertificates(within time.Duration) ([]*ExpiringCertificate, error) {
+	deadline := time.Now().Add(within)
+
+	apps, err := listAll(func(paging Paging) ([]*AppQueryResponse, error) {
+		return c.ListApps(&AppQuery{Paging: paging, AuthMethod: AppAuthMethodSAML})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var expiring []*ExpiringCertificate
+	for _, summary := range apps {
+		app, err := c.GetApp(summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		if app.SSO == nil || app.SSO.Certificate == nil || app.SSO.Certificate.Value == "" {
+			continue
+		}
+
+		notAfter, err := app.SSO.Certificate.ExpiresAt()
+		if err != nil || notAfter.Before(deadline) {
+			expiring = append(expiring, &ExpiringCertificate{
+				AppID:       app.ID,
+				AppName:     app.Name,
+				Certificate: app.SSO.Certificate,
+				NotAfter:    notAfter,
+				Err:         err,
+			})
+		}
+	}
+
+	slices.SortStableFunc(expiring, func(a, b *ExpiringCertificate) int {
+		return a.NotAfter.Compare(b.NotAfter)
+	})
+	return expiring, nil
+}
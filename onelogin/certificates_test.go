@@ -0,0 +1,108 @@
+package onelogin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_ListExpiringAppCertificates() {
+	_, err := s.client.ListExpiringAppCertificates(30 * 24 * time.Hour)
+	s.Require().NoError(err)
+}
+
+func (s *OneLoginTestSuite) Test_RotateAppCertificate_missing_fields() {
+	s.Equal(ErrMissingField{"id"}, s.client.RotateAppCertificate(0, 1))
+	s.Equal(ErrMissingField{"certificate.id"}, s.client.RotateAppCertificate(1, 0))
+}
+
+func testCertificateDER(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
+
+func TestCertificate_ExpiresAt(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	der := testCertificateDER(t, notAfter)
+
+	pemCert := &Certificate{Value: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+	expiresAt, err := pemCert.ExpiresAt()
+	require.NoError(t, err)
+	require.Equal(t, notAfter, expiresAt)
+
+	derCert := &Certificate{Value: base64.StdEncoding.EncodeToString(der)}
+	expiresAt, err = derCert.ExpiresAt()
+	require.NoError(t, err)
+	require.Equal(t, notAfter, expiresAt)
+
+	_, err = (&Certificate{Value: "not a certificate"}).Parse()
+	require.Error(t, err)
+}
+
+func TestListExpiringAppCertificates(t *testing.T) {
+	soon := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	values := map[int]string{
+		1: "not a certificate",
+		2: base64.StdEncoding.EncodeToString(testCertificateDER(t, soon)),
+		3: base64.StdEncoding.EncodeToString(testCertificateDER(t, soon.Add(365*24*time.Hour))),
+	}
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/2/apps" {
+			w.Write([]byte(`[{"id": 1}, {"id": 2}, {"id": 3}]`))
+			return
+		}
+		var id int
+		fmt.Sscanf(r.URL.Path, "/api/2/apps/%d", &id)
+		json.NewEncoder(w).Encode(&App{ID: id, SSO: &SSO{Certificate: &Certificate{ID: id, Value: values[id]}}})
+	})
+
+	expiring, err := c.ListExpiringAppCertificates(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	require.Len(t, expiring, 2)
+
+	// the unparseable certificate does not stop the scan
+	require.Equal(t, 1, expiring[0].AppID)
+	require.Error(t, expiring[0].Err)
+	require.Equal(t, 2, expiring[1].AppID)
+	require.NoError(t, expiring[1].Err)
+	require.Equal(t, soon, expiring[1].NotAfter)
+}
+
+func TestRotateAppCertificate(t *testing.T) {
+	var put map[string]interface{}
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&put))
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(`{"id": 7, "name": "Intranet", "connector_id": 110016, "sso": {"certificate": {"id": 1, "name": "old"}}}`))
+	})
+
+	require.NoError(t, c.RotateAppCertificate(7, 2))
+
+	// the whole app is written back with the new certificate
+	require.Equal(t, "Intranet", put["name"])
+	require.Equal(t, map[string]interface{}{"id": float64(2)}, put["sso"].(map[string]interface{})["certificate"])
+}
@@ -9,16 +9,41 @@ import (
 	"net/http"
 	urlpkg "net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
 const (
 	DefaultTimeout = 10 * time.Second
+
+	// tokenExpiryMargin is how long before expiry a cached access token
+	// is replaced, so it does not expire while a request is in flight
+	tokenExpiryMargin = time.Minute
 )
 
 type Client struct {
 	config     ClientConfig
 	httpClient *http.Client
+
+	// tokenMu guards the cached token, it is held while a new token is
+	// fetched so that concurrent requests wait for a single fetch
+	tokenMu     sync.Mutex
+	token       *AuthResponse
+	tokenExpiry time.Time
+
+	// mu guards the closed flag and is never held across a request
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+
+	// dryRunRequests and rateLimit are guarded by mu
+	dryRunRequests []*DryRunRequest
+	rateLimit      *RateLimit
+
+	// parent is the client a client returned by WithContext was derived
+	// from, it holds the shared token and state above
+	parent *Client
+	ctx    context.Context
 }
 
 type ClientConfig struct {
@@ -26,6 +51,17 @@ type ClientConfig struct {
 	ClientSecret string
 	Subdomain    string
 	Timeout      time.Duration
+
+	// DryRun records mutating requests instead of sending them, see
+	// DryRunRequests.  GET requests are still sent.
+	DryRun bool
+
+	// AuditSink receives a record of every mutating request, see
+	// AuditRecord.  AuditBeforeState fetches the state of the resource a
+	// request changes, or the resource a sub-resource belongs to, before
+	// the request is sent.
+	AuditSink        AuditSink
+	AuditBeforeState bool
 }
 
 type AuthResponse struct {
@@ -69,11 +105,147 @@ func NewClient(config ClientConfig) (*Client, error) {
 	// Attempt to authenticate
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 	defer cancel()
-	_, err := c.getToken(ctx)
+	_, err := c.accessToken(ctx)
 
 	return c, err
 }
 
+// Close waits for in-flight requests to finish and revokes the client's
+// access token.  Requests made after Close return ErrClientClosed.  If
+// ctx is done before the in-flight requests finish the token is left
+// unrevoked and ctx's error is returned.
+func (c *Client) Close(ctx context.Context) error {
+	c = c.root()
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	c.tokenMu.Lock()
+	token := c.token
+	c.token = nil
+	c.tokenMu.Unlock()
+
+	if token == nil {
+		return nil
+	}
+	return c.revokeToken(ctx, token.AccessToken)
+}
+
+// RevokeToken revokes an access token minted with the client's
+// credentials, including tokens obtained outside of this client
+//
+// https://developers.onelogin.com/api-docs/2/oauth20-tokens/revoke-tokens-2
+func (c *Client) RevokeToken(token string) error {
+	if token == "" {
+		return ErrMissingField{"access_token"}
+	}
+
+	ctx, cancel := context.WithTimeout(c.context(), c.config.Timeout)
+	defer cancel()
+	return c.revokeToken(ctx, token)
+}
+
+func (c *Client) revokeToken(ctx context.Context, token string) error {
+	revokeURL := fmt.Sprintf("https://%s.onelogin.com/auth/oauth2/revoke", c.config.Subdomain)
+
+	jsonData, _ := json.Marshal(map[string]string{
+		"access_token": token,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, revokeURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.config.ClientID, c.config.ClientSecret)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token revocation failed with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WithContext returns a client that makes its requests with ctx, so that
+// its cancellation and values such as the audit actor apply to them.
+// The returned client shares its token and state with c.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{
+		config:     c.config,
+		httpClient: c.httpClient,
+		parent:     c.root(),
+		ctx:        ctx,
+	}
+}
+
+// root returns the client holding the shared state
+func (c *Client) root() *Client {
+	if c.parent != nil {
+		return c.parent
+	}
+	return c
+}
+
+// context returns the context set by WithContext
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// accessToken returns the cached access token, minting a new one when
+// none is cached or the cached token is about to expire
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c = c.root()
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.token != nil && time.Now().Before(c.tokenExpiry) {
+		return c.token.AccessToken, nil
+	}
+
+	authResp, err := c.getToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = authResp
+	c.tokenExpiry = time.Now().Add(time.Duration(authResp.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return authResp.AccessToken, nil
+}
+
+// beginRequest registers an in-flight request, it fails once the client
+// is closed
+func (c *Client) beginRequest() error {
+	c = c.root()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClientClosed{}
+	}
+	c.inflight.Add(1)
+	return nil
+}
+
 func (c *Client) getToken(ctx context.Context) (*AuthResponse, error) {
 	authURL := fmt.Sprintf("https://%s.onelogin.com/auth/oauth2/v2/token", c.config.Subdomain)
 
@@ -126,17 +298,39 @@ type oneloginRequest struct {
 	body        io.Reader
 	queryParams map[string]string
 	respModel   interface{}
+
+	// readOnly marks a POST that does not change the tenant, such as a
+	// login, so that it is still sent in dry run mode
+	readOnly bool
+}
+
+// mutating reports whether a request changes the tenant
+func (req *oneloginRequest) mutating() bool {
+	return req.method != GET && !req.readOnly
 }
 
 func (c *Client) execRequest(req *oneloginRequest) error {
-	return c.execRequestContext(context.Background(), req)
+	return c.execRequestContext(c.context(), req)
 }
 
 func (c *Client) execRequestContext(ctx context.Context, req *oneloginRequest) error {
+	if err := c.beginRequest(); err != nil {
+		return err
+	}
+	defer c.root().inflight.Done()
+
 	// add configured timeout to context
 	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
 	defer cancel()
 
+	if c.config.AuditSink != nil && req.mutating() {
+		return c.auditRequest(ctx, req)
+	}
+	return c.sendRequest(ctx, req)
+}
+
+// sendRequest sends a request, or records it in dry run mode
+func (c *Client) sendRequest(ctx context.Context, req *oneloginRequest) error {
 	url := fmt.Sprintf("https://%s.onelogin.com%s", c.config.Subdomain, req.path)
 	if req.queryParams != nil && len(req.queryParams) > 0 {
 		queryParams := urlpkg.Values{}
@@ -147,17 +341,21 @@ func (c *Client) execRequestContext(ctx context.Context, req *oneloginRequest) e
 		url += "?" + queryParams.Encode()
 	}
 
+	if c.config.DryRun && req.mutating() {
+		return c.recordDryRun(req)
+	}
+
 	httpReq, err := http.NewRequestWithContext(ctx, string(req.method), url, req.body)
 	if err != nil {
 		return err
 	}
 
-	authResp, err := c.getToken(ctx)
+	accessToken, err := c.accessToken(ctx)
 	if err != nil {
 		return err
 	}
 
-	httpReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", authResp.AccessToken))
+	httpReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 	httpReq.Header.Add("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(httpReq)
@@ -165,10 +363,11 @@ func (c *Client) execRequestContext(ctx context.Context, req *oneloginRequest) e
 		return err
 	}
 	defer resp.Body.Close()
+	c.updateRateLimit(resp.Header)
 
 	if resp.StatusCode/100 != 2 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("request failed with status code %d\n%s", resp.StatusCode, string(bodyBytes))
+		return ErrRequestFailed{StatusCode: resp.StatusCode, Body: bodyBytes}
 	}
 
 	if req.respModel != nil {
@@ -191,3 +390,34 @@ func addPagingParams(queryParams map[string]string, paging *Paging) map[string]s
 
 	return queryParams
 }
+
+// v1PagingParams adds the cursor paging parameters used by the v1 APIs,
+// which take after_cursor in place of cursor and do not support page
+func v1PagingParams(queryParams map[string]string, paging *Paging) map[string]string {
+	if paging.Limit > 0 {
+		queryParams["limit"] = strconv.Itoa(paging.Limit)
+	}
+	if paging.Cursor != "" {
+		queryParams["after_cursor"] = paging.Cursor
+	}
+
+	return queryParams
+}
+
+// listAllLimit is the page size used when listing every item
+const listAllLimit = 100
+
+// listAll calls list for each page until a short page is returned
+func listAll[T any](list func(paging Paging) ([]T, error)) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		items, err := list(Paging{Limit: listAllLimit, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < listAllLimit {
+			return all, nil
+		}
+	}
+}
@@ -1,5 +1,16 @@
 package onelogin
 
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
 func (s *OneLoginTestSuite) Test_NewClient_default_timeout() {
 	// Test default timeout value set
 	client, err := NewClient(ClientConfig{})
@@ -11,3 +22,100 @@ func (s *OneLoginTestSuite) Test_NewClient_default_timeout() {
 func (s *OneLoginTestSuite) Test_NewClient_success() {
 	// Test accomplished in onelogin_test setup for use in other test routines
 }
+
+func (s *OneLoginTestSuite) Test_Close() {
+	client, err := NewClient(s.client.config)
+	s.Require().NoError(err)
+
+	_, err = client.ListRoles(&RoleQuery{Paging: Paging{Limit: 1}})
+	s.Require().NoError(err)
+
+	err = client.Close(context.Background())
+	s.Require().NoError(err)
+
+	_, err = client.ListRoles(&RoleQuery{Paging: Paging{Limit: 1}})
+	s.Equal(ErrClientClosed{}, err)
+}
+
+func (s *OneLoginTestSuite) Test_RevokeToken_missing_fields() {
+	err := s.client.RevokeToken("")
+	s.Equal(ErrMissingField{"access_token"}, err)
+}
+
+func TestClient_Close_without_token(t *testing.T) {
+	client := &Client{config: ClientConfig{Timeout: DefaultTimeout}}
+	require.NoError(t, client.Close(context.Background()))
+
+	_, err := client.GetUser(1)
+	require.Equal(t, ErrClientClosed{}, err)
+
+	_, err = client.GetEmbedApps("user@example.com", "token")
+	require.Equal(t, ErrClientClosed{}, err)
+
+	_, err = client.GetOIDCAppToken(&OIDCTokenRequest{ClientID: "id", ClientSecret: "secret"})
+	require.Equal(t, ErrClientClosed{}, err)
+
+	_, err = client.FetchSPMetadata("https://sp.example.com/metadata")
+	require.Equal(t, ErrClientClosed{}, err)
+}
+
+type blockingTransport struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	close(t.entered)
+	<-t.release
+	return nil, errors.New("released")
+}
+
+func TestClient_token_fetch_does_not_hold_mu(t *testing.T) {
+	transport := &blockingTransport{entered: make(chan struct{}), release: make(chan struct{})}
+	client := &Client{
+		config:     ClientConfig{Timeout: DefaultTimeout},
+		httpClient: &http.Client{Transport: transport},
+	}
+
+	fetched := make(chan error)
+	go func() {
+		_, err := client.accessToken(context.Background())
+		fetched <- err
+	}()
+	<-transport.entered
+
+	// the client's state stays available while the token is fetched
+	require.Empty(t, client.DryRunRequests())
+	_, ok := client.RateLimit()
+	require.False(t, ok)
+	require.NoError(t, client.beginRequest())
+	client.inflight.Done()
+
+	close(transport.release)
+	require.Error(t, <-fetched)
+}
+
+// handlerTransport serves a client's requests with a handler
+type handlerTransport struct {
+	http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// newTestClient returns a client with a cached token whose requests are
+// served by handler
+func newTestClient(config ClientConfig, handler http.HandlerFunc) *Client {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &Client{
+		config:      config,
+		httpClient:  &http.Client{Transport: handlerTransport{handler}},
+		token:       &AuthResponse{AccessToken: "token"},
+		tokenExpiry: time.Now().Add(time.Hour),
+	}
+}
@@ -0,0 +1,57 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// CustomAttribute is the definition of a custom user field, users hold
+// their values in CustomAttributes keyed by Shortname
+// https://developers.onelogin.com/api-docs/2/users/list-custom-attributes
+type CustomAttribute struct {
+	ID        int    `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Shortname string `json:"shortname"`
+	Position  *int   `json:"position,omitempty"`
+}
+
+// ListCustomAttributes lists the custom user field definitions
+func (c *Client) ListCustomAttributes() ([]*CustomAttribute, error) {
+	var attributes []*CustomAttribute
+	err := c.exec(GET, "/api/2/users/custom_attributes", nil, &attributes)
+	return attributes, err
+}
+
+// https://developers.onelogin.com/api-docs/2/users/create-custom-attribute
+func (c *Client) CreateCustomAttribute(attribute *CustomAttribute) (*CustomAttribute, error) {
+	if attribute.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+	if attribute.Shortname == "" {
+		return nil, ErrMissingField{"shortname"}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"user_field": map[string]string{
+			"name":      attribute.Name,
+			"shortname": attribute.Shortname,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var created CustomAttribute
+	err = c.exec(POST, "/api/2/users/custom_attributes", bytes.NewReader(body), &created)
+	if err != nil {
+		return nil, err
+	}
+	attribute.ID = created.ID
+	return attribute, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/users/delete-custom-attribute
+func (c *Client) DeleteCustomAttribute(id int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/users/custom_attributes/%v", id), nil, nil)
+}
@@ -0,0 +1,12 @@
+package onelogin
+
+func (s *OneLoginTestSuite) Test_ListCustomAttributes() {
+	attributes, err := s.client.ListCustomAttributes()
+	s.Require().NoError(err)
+	for _, attribute := range attributes {
+		s.NotEmpty(attribute.Shortname)
+	}
+
+	_, err = s.client.CreateCustomAttribute(&CustomAttribute{Name: "Cost Center"})
+	s.Equal(ErrMissingField{"shortname"}, err)
+}
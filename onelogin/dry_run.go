@@ -0,0 +1,72 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// DryRunRequest is a mutating request recorded instead of sent while the
+// client is in dry run mode
+type DryRunRequest struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+}
+
+// DryRunRequests returns the requests recorded in dry run mode, in the
+// order they would have been sent.  Calls that were recorded return
+// their zero value, so ids of resources that would have been created
+// are 0.  Calls that read back a resource they created or update skip
+// the read for such ids.
+func (c *Client) DryRunRequests() []*DryRunRequest {
+	c = c.root()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	requests := make([]*DryRunRequest, len(c.dryRunRequests))
+	copy(requests, c.dryRunRequests)
+	return requests
+}
+
+// ResetDryRunRequests discards the recorded requests
+func (c *Client) ResetDryRunRequests() {
+	c = c.root()
+	c.mu.Lock()
+	c.dryRunRequests = nil
+	c.mu.Unlock()
+}
+
+func (c *Client) recordDryRun(req *oneloginRequest) error {
+	recorded := &DryRunRequest{
+		Method: string(req.method),
+		Path:   req.path,
+	}
+	if len(req.queryParams) > 0 {
+		recorded.Query = map[string]string{}
+		for key, value := range req.queryParams {
+			recorded.Query[key] = value
+		}
+	}
+	if req.body != nil {
+		body, err := io.ReadAll(req.body)
+		if err != nil {
+			return err
+		}
+		if len(body) > 0 {
+			recorded.Body = body
+		}
+	}
+
+	root := c.root()
+	root.mu.Lock()
+	root.dryRunRequests = append(root.dryRunRequests, recorded)
+	root.mu.Unlock()
+	return nil
+}
+
+// dryRunCreated reports whether id is that of a resource created in dry
+// run mode, which does not exist and cannot be read back
+func (c *Client) dryRunCreated(id int) bool {
+	return c.config.DryRun && id == 0
+}
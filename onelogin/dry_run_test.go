@@ -0,0 +1,68 @@
+package onelogin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	// no credentials are needed since nothing is sent
+	c := &Client{config: ClientConfig{DryRun: true}}
+
+	user, err := c.CreateUser(&User{UserName: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	require.Zero(t, user.ID)
+
+	require.NoError(t, c.DeleteRole(5))
+	require.NoError(t, c.SetAppBrand(7, 0))
+
+	require.Equal(t, []*DryRunRequest{
+		{
+			Method: "POST",
+			Path:   "/api/2/users",
+			Query:  map[string]string{"mappings": "async", "validate_policy": "true"},
+			Body:   []byte(`{"username":"alice","email":"alice@example.com"}`),
+		},
+		{Method: "DELETE", Path: "/api/2/roles/5"},
+		{Method: "PUT", Path: "/api/2/apps/7", Body: []byte(`{"brand_id":null}`)},
+	}, c.DryRunRequests())
+
+	c.ResetDryRunRequests()
+	require.Empty(t, c.DryRunRequests())
+}
+
+func TestOneloginRequest_mutating(t *testing.T) {
+	require.False(t, (&oneloginRequest{method: GET}).mutating())
+	require.True(t, (&oneloginRequest{method: PUT}).mutating())
+	require.False(t, (&oneloginRequest{method: POST, readOnly: true}).mutating())
+}
+
+func TestDryRun_applyPlan(t *testing.T) {
+	// any request that is sent fails since the client has no http client
+	c := &Client{config: ClientConfig{DryRun: true}}
+
+	desired := &DesiredState{
+		Roles: []*DesiredRole{{Name: "Ops", Apps: []string{"Intranet"}, Users: []string{"alice"}}},
+	}
+	plan, err := computePlan(desired, testLiveState(), nil)
+	require.NoError(t, err)
+	require.Equal(t, PlanCreate, plan.Changes[0].Action)
+
+	require.NoError(t, c.ApplyPlan(plan))
+	require.Equal(t, []*DryRunRequest{
+		{Method: "POST", Path: "/api/2/roles", Body: []byte(`{"name":"Ops"}`)},
+		{Method: "PUT", Path: "/api/2/roles/0/apps", Body: []byte(`[1]`)},
+		{Method: "POST", Path: "/api/2/roles/0/users", Body: []byte(`[10]`)},
+	}, c.DryRunRequests())
+}
+
+func TestDryRun_createOIDCApp(t *testing.T) {
+	c := &Client{config: ClientConfig{DryRun: true}}
+
+	app, err := c.CreateOIDCApp(&App{Name: "Portal", Configuration: &Configuration{RedirectURI: "https://portal/callback"}})
+	require.NoError(t, err)
+	require.Zero(t, app.ID)
+	require.Nil(t, app.SSO)
+	require.Len(t, c.DryRunRequests(), 1)
+}
@@ -0,0 +1,84 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	urlpkg "net/url"
+)
+
+// EmbedAppsURL is the legacy endpoint listing the apps shown to a user,
+// it is authenticated with the account's embedding token
+const EmbedAppsURL = "https://api.onelogin.com/client/apps/embed2"
+
+// EmbedApp is an app as returned by the legacy embed endpoint
+type EmbedApp struct {
+	ID                int    `xml:"id"`
+	Name              string `xml:"name"`
+	Icon              string `xml:"icon"`
+	Provisioned       bool   `xml:"provisioned"`
+	ExtensionRequired bool   `xml:"extension_required"`
+	Personal          bool   `xml:"personal"`
+	LoginID           int    `xml:"login_id"`
+}
+
+type xmlEmbedApps struct {
+	Apps []*EmbedApp `xml:"app"`
+}
+
+// GetEmbedApps lists the apps a user can see in the portal.  The token is
+// the embedding token from the account settings, not an API credential.
+//
+// https://developers.onelogin.com/api-docs/1/embed-apps/get-apps-to-embed-for-a-user
+func (c *Client) GetEmbedApps(email, token string) ([]*EmbedApp, error) {
+	if email == "" {
+		return nil, ErrMissingField{"email"}
+	}
+	if token == "" {
+		return nil, ErrMissingField{"token"}
+	}
+
+	if err := c.beginRequest(); err != nil {
+		return nil, err
+	}
+	defer c.root().inflight.Done()
+
+	params := urlpkg.Values{}
+	params.Set("email", email)
+	params.Set("token", token)
+
+	ctx, cancel := context.WithTimeout(c.context(), c.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, EmbedAppsURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, ErrRequestFailed{StatusCode: resp.StatusCode, Body: bodyBytes}
+	}
+
+	return ParseEmbedApps(bodyBytes)
+}
+
+// ParseEmbedApps parses the XML returned by the embed endpoint
+func ParseEmbedApps(data []byte) ([]*EmbedApp, error) {
+	var embed xmlEmbedApps
+	if err := xml.Unmarshal(data, &embed); err != nil {
+		return nil, fmt.Errorf("failed to parse embed apps: %w", err)
+	}
+	return embed.Apps, nil
+}
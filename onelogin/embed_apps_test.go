@@ -0,0 +1,54 @@
+package onelogin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEmbedApps(t *testing.T) {
+	apps, err := ParseEmbedApps([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<apps>
+  <app>
+    <id>391</id>
+    <icon>https://s3.amazonaws.com/onelogin-assets/images/icons/square/salesforce.png</icon>
+    <name>Salesforce</name>
+    <provisioned>1</provisioned>
+    <extension_required>false</extension_required>
+    <personal>false</personal>
+    <login_id>1234</login_id>
+  </app>
+  <app>
+    <id>392</id>
+    <name>Intranet</name>
+    <provisioned>0</provisioned>
+    <extension_required>true</extension_required>
+  </app>
+</apps>`))
+	require.NoError(t, err)
+	require.Equal(t, []*EmbedApp{
+		{
+			ID:          391,
+			Name:        "Salesforce",
+			Icon:        "https://s3.amazonaws.com/onelogin-assets/images/icons/square/salesforce.png",
+			Provisioned: true,
+			LoginID:     1234,
+		},
+		{
+			ID:                392,
+			Name:              "Intranet",
+			ExtensionRequired: true,
+		},
+	}, apps)
+
+	_, err = ParseEmbedApps([]byte(`not xml`))
+	require.Error(t, err)
+}
+
+func TestGetEmbedApps_missing_fields(t *testing.T) {
+	c := &Client{}
+	_, err := c.GetEmbedApps("", "token")
+	require.Equal(t, ErrMissingField{"email"}, err)
+	_, err = c.GetEmbedApps("user@example.com", "")
+	require.Equal(t, ErrMissingField{"token"}, err)
+}
@@ -0,0 +1,35 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// parseEnum looks up the value for a name in an enum's name table
+func parseEnum[T ~int](name, kind string, names map[T]string) (T, error) {
+	for v, n := range names {
+		if n == name {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s: %q", kind, name)
+}
+
+// unmarshalEnum decodes an enum from either its numeric API value
+// or its name
+func unmarshalEnum[T ~int](data []byte, kind string, names map[T]string) (T, error) {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return 0, err
+		}
+		return parseEnum(name, kind, names)
+	}
+
+	var i int
+	if err := json.Unmarshal(data, &i); err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", kind, string(data))
+	}
+	return T(i), nil
+}
@@ -1,5 +1,7 @@
 package onelogin
 
+import "fmt"
+
 // ErrNotImplemented is returned when a method is not implemented
 type ErrNotImplemented struct{}
 
@@ -22,3 +24,85 @@ type ErrOneloginAPIBroken struct{}
 func (e ErrOneloginAPIBroken) Error() string {
 	return "Onelogin API is broken"
 }
+
+// ErrInvalidConfiguration is returned when an app's configuration does
+// not match its auth method
+type ErrInvalidConfiguration struct {
+	AuthMethod AppAuthMethod
+	Field      string
+	Reason     string
+}
+
+func (e ErrInvalidConfiguration) Error() string {
+	if e.Field == "" {
+		return "invalid " + e.AuthMethod.String() + " configuration: " + e.Reason
+	}
+	return "invalid " + e.AuthMethod.String() + " configuration: " + e.Field + ": " + e.Reason
+}
+
+// ErrRequestFailed is returned when the API responds with a non 2xx status
+type ErrRequestFailed struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e ErrRequestFailed) Error() string {
+	return fmt.Sprintf("request failed with status code %d\n%s", e.StatusCode, string(e.Body))
+}
+
+// ErrInvalidResponse is returned when a successful response does not
+// hold what the API documents
+type ErrInvalidResponse struct {
+	Reason string
+}
+
+func (e ErrInvalidResponse) Error() string {
+	return "invalid response: " + e.Reason
+}
+
+// ErrInvalidCredentials is returned when a login or factor is rejected
+type ErrInvalidCredentials struct {
+	Message string
+}
+
+func (e ErrInvalidCredentials) Error() string {
+	return "invalid credentials: " + e.Message
+}
+
+// ErrUserLocked is returned when a login is attempted for a locked user,
+// which the API reports with status code 423
+type ErrUserLocked struct {
+	Message string
+}
+
+func (e ErrUserLocked) Error() string {
+	return "user is locked: " + e.Message
+}
+
+// ErrMFATimeout is returned when a factor is not verified in time
+type ErrMFATimeout struct{}
+
+func (e ErrMFATimeout) Error() string {
+	return "timed out waiting for factor verification"
+}
+
+// ErrClientClosed is returned for requests made after Client.Close
+type ErrClientClosed struct{}
+
+func (e ErrClientClosed) Error() string {
+	return "client is closed"
+}
+
+// ErrBatchSkipped is the result of a batch item that was not run because
+// the batch's context was done first
+type ErrBatchSkipped struct {
+	Cause error
+}
+
+func (e ErrBatchSkipped) Error() string {
+	return fmt.Sprintf("batch item skipped: %v", e.Cause)
+}
+
+func (e ErrBatchSkipped) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,111 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EventCheckpoint records how far an EventTailer has read
+type EventCheckpoint struct {
+	// Since is the creation time of the newest event delivered
+	Since time.Time `json:"since"`
+
+	// Seen holds the ids and creation times of the events delivered
+	// inside the overlap window, used to drop duplicates when the
+	// window is read again
+	Seen map[int]time.Time `json:"seen,omitempty"`
+}
+
+// CheckpointStore persists an EventCheckpoint between runs
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if none has been saved
+	Load() (*EventCheckpoint, error)
+	Save(checkpoint *EventCheckpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory, it is lost
+// when the process exits
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *EventCheckpoint
+}
+
+func (s *MemoryCheckpointStore) Load() (*EventCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyCheckpoint(s.checkpoint), nil
+}
+
+func (s *MemoryCheckpointStore) Save(checkpoint *EventCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = copyCheckpoint(checkpoint)
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint as JSON in a file.  Saves
+// write a temporary file and rename it so a crash never leaves a
+// partially written checkpoint behind.
+type FileCheckpointStore struct {
+	Path string
+}
+
+func (s *FileCheckpointStore) Load() (*EventCheckpoint, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint EventCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (s *FileCheckpointStore) Save(checkpoint *EventCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+func copyCheckpoint(checkpoint *EventCheckpoint) *EventCheckpoint {
+	if checkpoint == nil {
+		return nil
+	}
+	cp := &EventCheckpoint{
+		Since: checkpoint.Since,
+		Seen:  make(map[int]time.Time, len(checkpoint.Seen)),
+	}
+	for id, createdAt := range checkpoint.Seen {
+		cp.Seen[id] = createdAt
+	}
+	return cp
+}
@@ -0,0 +1,222 @@
+package onelogin
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultEventPollInterval = 30 * time.Second
+	DefaultEventOverlap      = 5 * time.Minute
+)
+
+// eventLister is the part of Client used by EventTailer
+type eventLister interface {
+	ListEvents(query *EventQuery) ([]*Event, *Pagination, error)
+}
+
+// EventTailerConfig configures an EventTailer
+type EventTailerConfig struct {
+	// Query filters the events tailed.  Since is the starting point
+	// when the store holds no checkpoint, if it is also zero tailing
+	// starts from the time of the first poll.  Cursor and Until are
+	// managed by the tailer and ignored.
+	Query EventQuery
+
+	// Store persists the tailer's position, defaults to a
+	// MemoryCheckpointStore
+	Store CheckpointStore
+
+	// Interval is the time between polls, defaults to
+	// DefaultEventPollInterval
+	Interval time.Duration
+
+	// Overlap is how far before the checkpoint each poll reads again
+	// to pick up events that were indexed late, defaults to
+	// DefaultEventOverlap.  Events already delivered are dropped.
+	Overlap time.Duration
+}
+
+// EventTailer polls the events API for new events and delivers each
+// event once, in creation order, saving its position after every event
+type EventTailer struct {
+	lister eventLister
+	config EventTailerConfig
+	now    func() time.Time
+}
+
+func (c *Client) NewEventTailer(config EventTailerConfig) *EventTailer {
+	return newEventTailer(c, config)
+}
+
+func newEventTailer(lister eventLister, config EventTailerConfig) *EventTailer {
+	if config.Store == nil {
+		config.Store = &MemoryCheckpointStore{}
+	}
+	if config.Interval == 0 {
+		config.Interval = DefaultEventPollInterval
+	}
+	if config.Overlap == 0 {
+		config.Overlap = DefaultEventOverlap
+	}
+
+	return &EventTailer{
+		lister: lister,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run polls until ctx is cancelled or handler returns an error.  An
+// event is only checkpointed after handler returns nil for it, so an
+// event whose handler failed is delivered again on restart.
+func (t *EventTailer) Run(ctx context.Context, handler func(*Event) error) error {
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Poll(ctx, handler); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Events runs the tailer in the background and delivers events on the
+// returned channel.  The error channel receives the error that stopped
+// the tailer, and both channels are closed when it stops.
+func (t *EventTailer) Events(ctx context.Context) (<-chan *Event, <-chan error) {
+	events := make(chan *Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		errs <- t.Run(ctx, func(event *Event) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return events, errs
+}
+
+// Poll reads every event since the checkpoint once and returns
+func (t *EventTailer) Poll(ctx context.Context, handler func(*Event) error) error {
+	checkpoint, err := t.config.Store.Load()
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		checkpoint = &EventCheckpoint{Since: t.config.Query.Since}
+		if checkpoint.Since.IsZero() {
+			checkpoint.Since = t.now()
+		}
+		if err := t.config.Store.Save(checkpoint); err != nil {
+			return err
+		}
+	}
+	if checkpoint.Seen == nil {
+		checkpoint.Seen = map[int]time.Time{}
+	}
+
+	events, err := t.fetch(ctx, checkpoint.Since.Add(-t.config.Overlap))
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if _, ok := checkpoint.Seen[event.ID]; ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+
+		// an event without a creation time is kept from the checkpoint
+		// it was delivered at, so it is only pruned once the checkpoint
+		// moves past it
+		createdAt := eventCreatedAt(event)
+		if createdAt.IsZero() {
+			createdAt = checkpoint.Since
+		}
+		checkpoint.Seen[event.ID] = createdAt
+		if createdAt.After(checkpoint.Since) {
+			checkpoint.Since = createdAt
+		}
+		pruneCheckpoint(checkpoint, t.config.Overlap)
+
+		if err := t.config.Store.Save(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetch reads every page of events created at or after since and
+// returns them oldest first
+func (t *EventTailer) fetch(ctx context.Context, since time.Time) ([]*Event, error) {
+	query := t.config.Query
+	query.Since = since
+	query.Until = time.Time{}
+	query.Cursor = ""
+
+	var events []*Event
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, pagination, err := t.lister.ListEvents(&query)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+
+		if pagination == nil || pagination.AfterCursor == "" || len(page) == 0 {
+			break
+		}
+		query.Cursor = pagination.AfterCursor
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		ti, tj := eventCreatedAt(events[i]), eventCreatedAt(events[j])
+		if ti.Equal(tj) {
+			return events[i].ID < events[j].ID
+		}
+		return ti.Before(tj)
+	})
+	return events, nil
+}
+
+// pruneCheckpoint drops seen ids that have fallen out of the overlap window
+func pruneCheckpoint(checkpoint *EventCheckpoint, overlap time.Duration) {
+	cutoff := checkpoint.Since.Add(-overlap)
+	for id, createdAt := range checkpoint.Seen {
+		if createdAt.Before(cutoff) {
+			delete(checkpoint.Seen, id)
+		}
+	}
+}
+
+func eventCreatedAt(event *Event) time.Time {
+	if event.CreatedAt == nil {
+		return time.Time{}
+	}
+	return *event.CreatedAt
+}
@@ -0,0 +1,153 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventLister serves a fixed set of events two per page
+type fakeEventLister struct {
+	events  []*Event
+	queries []EventQuery
+}
+
+func (f *fakeEventLister) ListEvents(query *EventQuery) ([]*Event, *Pagination, error) {
+	f.queries = append(f.queries, *query)
+
+	var matching []*Event
+	for _, e := range f.events {
+		if e.CreatedAt == nil || !e.CreatedAt.Before(query.Since) {
+			matching = append(matching, e)
+		}
+	}
+
+	start := 0
+	if query.Cursor != "" {
+		start = int(query.Cursor[0] - '0')
+	}
+	end := min(start+2, len(matching))
+	pagination := &Pagination{}
+	if end < len(matching) {
+		pagination.AfterCursor = string(rune('0' + end))
+	}
+	return matching[start:end], pagination, nil
+}
+
+func testEvent(id int, createdAt time.Time) *Event {
+	return &Event{ID: id, CreatedAt: &createdAt}
+}
+
+func TestEventTailer_Poll(t *testing.T) {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	lister := &fakeEventLister{events: []*Event{
+		testEvent(3, base.Add(3*time.Second)),
+		testEvent(1, base.Add(1*time.Second)),
+		testEvent(2, base.Add(1*time.Second)),
+	}}
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	config := EventTailerConfig{
+		Query: EventQuery{Since: base, UserID: 9},
+		Store: store,
+	}
+
+	var delivered []int
+	handler := func(e *Event) error {
+		delivered = append(delivered, e.ID)
+		return nil
+	}
+
+	tailer := newEventTailer(lister, config)
+	require.NoError(t, tailer.Poll(context.Background(), handler))
+	require.Equal(t, []int{1, 2, 3}, delivered)
+	require.Equal(t, 9, lister.queries[0].UserID)
+	require.Equal(t, "2", lister.queries[1].Cursor)
+
+	// a new event arrives, and one is indexed late inside the overlap window
+	lister.events = append(lister.events,
+		testEvent(4, base.Add(4*time.Second)),
+		testEvent(5, base.Add(2*time.Second)),
+	)
+
+	// a restarted tailer resumes from the file checkpoint without duplicates
+	delivered = nil
+	tailer = newEventTailer(lister, config)
+	require.NoError(t, tailer.Poll(context.Background(), handler))
+	require.Equal(t, []int{5, 4}, delivered)
+
+	checkpoint, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, base.Add(4*time.Second), checkpoint.Since)
+	require.Len(t, checkpoint.Seen, 5)
+}
+
+func TestEventTailer_no_created_at(t *testing.T) {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	lister := &fakeEventLister{events: []*Event{{ID: 1}, testEvent(2, base.Add(time.Second))}}
+	tailer := newEventTailer(lister, EventTailerConfig{Query: EventQuery{Since: base}})
+
+	var delivered []int
+	handler := func(e *Event) error {
+		delivered = append(delivered, e.ID)
+		return nil
+	}
+	require.NoError(t, tailer.Poll(context.Background(), handler))
+	require.NoError(t, tailer.Poll(context.Background(), handler))
+	require.Equal(t, []int{1, 2}, delivered)
+}
+
+func TestEventTailer_handler_error(t *testing.T) {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	lister := &fakeEventLister{events: []*Event{
+		testEvent(1, base.Add(1*time.Second)),
+		testEvent(2, base.Add(2*time.Second)),
+	}}
+	store := &MemoryCheckpointStore{}
+	tailer := newEventTailer(lister, EventTailerConfig{Query: EventQuery{Since: base}, Store: store})
+
+	failure := errors.New("failed")
+	err := tailer.Poll(context.Background(), func(e *Event) error {
+		if e.ID == 2 {
+			return failure
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, failure)
+
+	// the failed event is delivered again
+	var delivered []int
+	err = tailer.Poll(context.Background(), func(e *Event) error {
+		delivered = append(delivered, e.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, delivered)
+}
+
+func TestEventTailer_Events(t *testing.T) {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	lister := &fakeEventLister{events: []*Event{testEvent(1, base.Add(time.Second))}}
+	tailer := newEventTailer(lister, EventTailerConfig{Query: EventQuery{Since: base}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := tailer.Events(ctx)
+
+	event := <-events
+	require.Equal(t, 1, event.ID)
+	cancel()
+
+	_, ok := <-events
+	require.False(t, ok)
+	require.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestFileCheckpointStore_missing(t *testing.T) {
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "missing.json")}
+	checkpoint, err := store.Load()
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+}
@@ -0,0 +1,236 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Event is a OneLogin event as returned by the v1 events API
+// https://developers.onelogin.com/api-docs/1/events/event-resource
+type Event struct {
+	ID                   int         `json:"id,omitempty"`
+	UUID                 string      `json:"uuid,omitempty"`
+	CreatedAt            *time.Time  `json:"created_at,omitempty"`
+	AccountID            int         `json:"account_id,omitempty"`
+	EventTypeID          EventTypeID `json:"event_type_id,omitempty"`
+	UserID               int         `json:"user_id,omitempty"`
+	UserName             string      `json:"user_name,omitempty"`
+	ActorUserID          int         `json:"actor_user_id,omitempty"`
+	ActorUserName        string      `json:"actor_user_name,omitempty"`
+	ActorSystem          string      `json:"actor_system,omitempty"`
+	AssumingActingUserID int         `json:"assuming_acting_user_id,omitempty"`
+	AppID                int         `json:"app_id,omitempty"`
+	AppName              string      `json:"app_name,omitempty"`
+	RoleID               int         `json:"role_id,omitempty"`
+	RoleName             string      `json:"role_name,omitempty"`
+	GroupID              int         `json:"group_id,omitempty"`
+	GroupName            string      `json:"group_name,omitempty"`
+	PolicyID             int         `json:"policy_id,omitempty"`
+	PolicyName           string      `json:"policy_name,omitempty"`
+	OTPDeviceID          int         `json:"otp_device_id,omitempty"`
+	OTPDeviceName        string      `json:"otp_device_name,omitempty"`
+	DirectoryID          int         `json:"directory_id,omitempty"`
+	DirectorySyncRunID   int         `json:"directory_sync_run_id,omitempty"`
+	ClientID             string      `json:"client_id,omitempty"`
+	ResourceTypeID       int         `json:"resource_type_id,omitempty"`
+	Resolution           *int        `json:"resolution,omitempty"`
+	IPAddr               string      `json:"ipaddr,omitempty"`
+	ProxyIP              string      `json:"proxy_ip,omitempty"`
+	Notes                string      `json:"notes,omitempty"`
+	CustomMessage        string      `json:"custom_message,omitempty"`
+	OperationName        string      `json:"operation_name,omitempty"`
+	ErrorDescription     string      `json:"error_description,omitempty"`
+	RiskScore            int         `json:"risk_score,omitempty"`
+	RiskReasons          string      `json:"risk_reasons,omitempty"`
+	RiskCookieID         string      `json:"risk_cookie_id,omitempty"`
+	BrowserFingerprint   *bool       `json:"browser_fingerprint,omitempty"`
+}
+
+// EventTypeID identifies the kind of an event.  Readable names are
+// available from the catalogue returned by ListEventTypes.
+type EventTypeID int
+
+// EventType describes an event type returned by the event types endpoint
+type EventType struct {
+	ID          EventTypeID `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+}
+
+// EventTypeCatalog maps event type ids to their descriptions
+type EventTypeCatalog map[EventTypeID]*EventType
+
+// Name returns the readable name of an event type, falling back to
+// the numeric id for types missing from the catalogue
+func (c EventTypeCatalog) Name(id EventTypeID) string {
+	if t, ok := c[id]; ok {
+		return t.Name
+	}
+	return strconv.Itoa(int(id))
+}
+
+// Lookup finds an event type by its readable name
+func (c EventTypeCatalog) Lookup(name string) (*EventType, bool) {
+	for _, t := range c {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// EventQuery holds the filters supported by ListEvents.  The events
+// API is cursor based, Paging.Cursor is sent as the after_cursor
+// and Paging.Page is ignored.
+type EventQuery struct {
+	Paging
+	ID          int
+	EventTypeID EventTypeID
+	UserID      int
+	ClientID    string
+	DirectoryID int
+	Resolution  string
+	Since       time.Time
+	Until       time.Time
+}
+
+// Pagination holds the cursors returned by the v1 APIs
+// https://developers.onelogin.com/api-docs/1/getting-started/using-query-parameters
+type Pagination struct {
+	BeforeCursor string `json:"before_cursor"`
+	AfterCursor  string `json:"after_cursor"`
+	PreviousLink string `json:"previous_link"`
+	NextLink     string `json:"next_link"`
+}
+
+// v1Status is the status block included in every v1 API response
+type v1Status struct {
+	Error   bool   `json:"error"`
+	Code    int    `json:"code"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// v1Response is the envelope wrapping every v1 API response, data
+// should be set to a pointer to the expected payload before decoding
+type v1Response struct {
+	Status     v1Status    `json:"status"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// ListEvents returns a single page of events along with the cursors
+// needed to fetch the next page.  Pass Pagination.AfterCursor back as
+// query.Cursor to continue, an empty AfterCursor marks the last page.
+//
+// https://developers.onelogin.com/api-docs/1/events/get-events
+func (c *Client) ListEvents(query *EventQuery) ([]*Event, *Pagination, error) {
+	var events []*Event
+	resp := v1Response{Data: &events}
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        "/api/1/events",
+		queryParams: eventQueryToParams(query),
+		respModel:   &resp,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Pagination == nil {
+		resp.Pagination = &Pagination{}
+	}
+	return events, resp.Pagination, nil
+}
+
+// https://developers.onelogin.com/api-docs/1/events/get-event-by-id
+func (c *Client) GetEvent(id int) (*Event, error) {
+	var events []*Event
+	err := c.execRequest(&oneloginRequest{
+		method:    GET,
+		path:      fmt.Sprintf("/api/1/events/%v", id),
+		respModel: &v1Response{Data: &events},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("event %v not found", id)
+	}
+	return events[0], nil
+}
+
+// https://developers.onelogin.com/api-docs/1/events/create-event
+func (c *Client) CreateEvent(event *Event) error {
+	if event.EventTypeID == 0 {
+		return ErrMissingField{"event_type_id"}
+	}
+	if event.AccountID == 0 {
+		return ErrMissingField{"account_id"}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return c.execRequest(&oneloginRequest{
+		method: POST,
+		path:   "/api/1/events",
+		body:   bytes.NewReader(body),
+	})
+}
+
+// ListEventTypes loads the catalogue of event types
+//
+// https://developers.onelogin.com/api-docs/1/events/event-types
+func (c *Client) ListEventTypes() (EventTypeCatalog, error) {
+	var types []*EventType
+	err := c.execRequest(&oneloginRequest{
+		method:    GET,
+		path:      "/api/1/events/types",
+		respModel: &v1Response{Data: &types},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make(EventTypeCatalog, len(types))
+	for _, t := range types {
+		catalog[t.ID] = t
+	}
+	return catalog, nil
+}
+
+func eventQueryToParams(query *EventQuery) map[string]string {
+	params := map[string]string{}
+
+	if query.ID != 0 {
+		params["id"] = strconv.Itoa(query.ID)
+	}
+	if query.EventTypeID != 0 {
+		params["event_type_id"] = strconv.Itoa(int(query.EventTypeID))
+	}
+	if query.UserID != 0 {
+		params["user_id"] = strconv.Itoa(query.UserID)
+	}
+	if query.ClientID != "" {
+		params["client_id"] = query.ClientID
+	}
+	if query.DirectoryID != 0 {
+		params["directory_id"] = strconv.Itoa(query.DirectoryID)
+	}
+	if query.Resolution != "" {
+		params["resolution"] = query.Resolution
+	}
+	if !query.Since.IsZero() {
+		params["since"] = query.Since.UTC().Format(time.RFC3339)
+	}
+	if !query.Until.IsZero() {
+		params["until"] = query.Until.UTC().Format(time.RFC3339)
+	}
+
+	return v1PagingParams(params, &query.Paging)
+}
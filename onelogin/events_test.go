@@ -0,0 +1,82 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_ListEvents() {
+	catalog, err := s.client.ListEventTypes()
+	s.Require().NoError(err)
+	s.Require().NotEmpty(catalog)
+
+	events, pagination, err := s.client.ListEvents(&EventQuery{
+		Paging: Paging{Limit: 2},
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(pagination)
+	s.LessOrEqual(len(events), 2)
+
+	if len(events) > 0 {
+		s.NotEqual("", catalog.Name(events[0].EventTypeID))
+
+		event, err := s.client.GetEvent(events[0].ID)
+		s.Require().NoError(err)
+		s.Equal(events[0].ID, event.ID)
+	}
+}
+
+func (s *OneLoginTestSuite) Test_CreateEvent_missing_fields() {
+	err := s.client.CreateEvent(&Event{})
+	s.Equal(ErrMissingField{"event_type_id"}, err)
+
+	err = s.client.CreateEvent(&Event{EventTypeID: 1})
+	s.Equal(ErrMissingField{"account_id"}, err)
+}
+
+func TestEventQueryToParams(t *testing.T) {
+	since := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	params := eventQueryToParams(&EventQuery{
+		Paging:      Paging{Limit: 10, Page: 3, Cursor: "abc"},
+		EventTypeID: 5,
+		UserID:      7,
+		ClientID:    "client",
+		DirectoryID: 9,
+		Resolution:  "1",
+		Since:       since,
+	})
+	require.Equal(t, map[string]string{
+		"limit":         "10",
+		"after_cursor":  "abc",
+		"event_type_id": "5",
+		"user_id":       "7",
+		"client_id":     "client",
+		"directory_id":  "9",
+		"resolution":    "1",
+		"since":         "2023-01-02T03:04:05Z",
+	}, params)
+}
+
+func TestV1Response_decode(t *testing.T) {
+	var events []*Event
+	resp := v1Response{Data: &events}
+	err := json.Unmarshal([]byte(`{
+		"status": {"error": false, "code": 200, "type": "success", "message": "Success"},
+		"pagination": {"before_cursor": null, "after_cursor": "next", "previous_link": null, "next_link": "https://x"},
+		"data": [{"id": 1, "event_type_id": 5, "created_at": "2023-01-02T03:04:05Z", "resolution": null}]
+	}`), &resp)
+	require.NoError(t, err)
+	require.Equal(t, "next", resp.Pagination.AfterCursor)
+	require.Len(t, events, 1)
+	require.Equal(t, EventTypeID(5), events[0].EventTypeID)
+
+	catalog := EventTypeCatalog{5: {ID: 5, Name: "USER_LOGGED_INTO_ONELOGIN"}}
+	require.Equal(t, "USER_LOGGED_INTO_ONELOGIN", catalog.Name(5))
+	require.Equal(t, "6", catalog.Name(6))
+	eventType, ok := catalog.Lookup("USER_LOGGED_INTO_ONELOGIN")
+	require.True(t, ok)
+	require.Equal(t, EventTypeID(5), eventType.ID)
+}
@@ -0,0 +1,62 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// GenerateInviteLink returns a link the user can follow to set their
+// password
+//
+// https://developers.onelogin.com/api-docs/1/invite-links/generate-invite-link
+func (c *Client) GenerateInviteLink(email string) (string, error) {
+	if email == "" {
+		return "", ErrMissingField{"email"}
+	}
+
+	body, err := json.Marshal(map[string]string{"email": email})
+	if err != nil {
+		return "", err
+	}
+
+	var links []string
+	err = c.execRequest(&oneloginRequest{
+		method:    POST,
+		path:      "/api/1/invites/get_invite_link",
+		body:      bytes.NewReader(body),
+		respModel: &v1Response{Data: &links},
+		readOnly:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(links) == 0 {
+		return "", ErrInvalidResponse{Reason: "invite link response has no link"}
+	}
+	return links[0], nil
+}
+
+// SendInviteLink emails an invite link to the user.  The link is sent to
+// personalEmail when it is set, otherwise to the user's email.
+//
+// https://developers.onelogin.com/api-docs/1/invite-links/send-invite-link
+func (c *Client) SendInviteLink(email, personalEmail string) error {
+	if email == "" {
+		return ErrMissingField{"email"}
+	}
+
+	request := map[string]string{"email": email}
+	if personalEmail != "" {
+		request["personal_email"] = personalEmail
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return c.execRequest(&oneloginRequest{
+		method: POST,
+		path:   "/api/1/invites/send_invite_link",
+		body:   bytes.NewReader(body),
+	})
+}
@@ -0,0 +1,47 @@
+package onelogin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_InviteLink_missing_fields() {
+	_, err := s.client.GenerateInviteLink("")
+	s.Equal(ErrMissingField{"email"}, err)
+
+	err = s.client.SendInviteLink("", "personal@example.com")
+	s.Equal(ErrMissingField{"email"}, err)
+}
+
+func (s *OneLoginTestSuite) Test_GenerateInviteLink() {
+	users, err := s.client.ListUsers(&UserQuery{
+		Paging: Paging{Limit: 1, Page: 1},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(users))
+
+	link, err := s.client.GenerateInviteLink(users[0].Email)
+	s.Require().NoError(err)
+	s.NotEmpty(link)
+}
+
+func TestGenerateInviteLink_no_link(t *testing.T) {
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": {"error": false, "code": 200, "type": "success", "message": "Success"}, "data": []}`))
+	})
+	_, err := c.GenerateInviteLink("alice@example.com")
+	require.Equal(t, ErrInvalidResponse{Reason: "invite link response has no link"}, err)
+}
+
+func TestGenerateInviteLink_dry_run(t *testing.T) {
+	// generating a link changes nothing, so it is sent in dry run mode
+	c := newTestClient(ClientConfig{DryRun: true}, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": {"error": false, "code": 200, "type": "success", "message": "Success"}, "data": ["https://example.onelogin.com/invite"]}`))
+	})
+	link, err := c.GenerateInviteLink("alice@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.onelogin.com/invite", link)
+	require.Empty(t, c.DryRunRequests())
+}
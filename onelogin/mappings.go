@@ -0,0 +1,152 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Mapping sets user attributes and roles when its conditions match
+// https://developers.onelogin.com/api-docs/2/user-mappings/overview
+type Mapping struct {
+	ID         int                 `json:"id,omitempty"`
+	Name       string              `json:"name"`
+	Match      string              `json:"match"`
+	Enabled    bool                `json:"enabled"`
+	Position   *int                `json:"position"`
+	Conditions []*MappingCondition `json:"conditions"`
+	Actions    []*MappingAction    `json:"actions"`
+}
+
+// MappingCondition compares a user attribute to a value.  Mappings and
+// app rules share the same conditions.
+type MappingCondition struct {
+	Source   string `json:"source"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// MappingAction sets a user attribute or role.  Mappings and app rules
+// share the same actions.
+type MappingAction struct {
+	Action string   `json:"action"`
+	Value  []string `json:"value"`
+}
+
+// ListMappings lists the enabled or the disabled mappings
+//
+// https://developers.onelogin.com/api-docs/2/user-mappings/list-mappings
+func (c *Client) ListMappings(enabled bool) ([]*Mapping, error) {
+	var mappings []*Mapping
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        "/api/2/mappings",
+		queryParams: map[string]string{"enabled": strconv.FormatBool(enabled)},
+		respModel:   &mappings,
+	})
+	return mappings, err
+}
+
+// https://developers.onelogin.com/api-docs/2/user-mappings/get-mapping
+func (c *Client) GetMapping(id int) (*Mapping, error) {
+	var mapping Mapping
+	err := c.exec(GET, fmt.Sprintf("/api/2/mappings/%v", id), nil, &mapping)
+	return &mapping, err
+}
+
+// https://developers.onelogin.com/api-docs/2/user-mappings/create-mapping
+func (c *Client) CreateMapping(mapping *Mapping) (*Mapping, error) {
+	if mapping.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+	if mapping.Match == "" {
+		return nil, ErrMissingField{"match"}
+	}
+
+	body, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var created struct {
+		ID int `json:"id"`
+	}
+	err = c.exec(POST, "/api/2/mappings", bytes.NewReader(body), &created)
+	if err != nil {
+		return nil, err
+	}
+	mapping.ID = created.ID
+	return mapping, nil
+}
+
+// SortMappings sets the order of the enabled mappings, ids lists every
+// enabled mapping by its new position.  It returns the ids in their new
+// order.
+//
+// https://developers.onelogin.com/api-docs/2/user-mappings/bulk-sort
+func (c *Client) SortMappings(ids []int) ([]int, error) {
+	body, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var sorted []int
+	err = c.exec(PUT, "/api/2/mappings/sort", bytes.NewReader(body), &sorted)
+	return sorted, err
+}
+
+// https://developers.onelogin.com/api-docs/2/user-mappings/delete-mapping
+func (c *Client) DeleteMapping(id int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/mappings/%v", id), nil, nil)
+}
+
+// AppRule sets app parameters or roles for the users of an app when its
+// conditions match
+// https://developers.onelogin.com/api-docs/2/app-rules/overview
+type AppRule struct {
+	ID         int                 `json:"id,omitempty"`
+	Name       string              `json:"name"`
+	Match      string              `json:"match"`
+	Enabled    bool                `json:"enabled"`
+	Position   *int                `json:"position"`
+	Conditions []*MappingCondition `json:"conditions"`
+	Actions    []*MappingAction    `json:"actions"`
+}
+
+// https://developers.onelogin.com/api-docs/2/app-rules/list-rules
+func (c *Client) ListAppRules(appID int) ([]*AppRule, error) {
+	var rules []*AppRule
+	err := c.exec(GET, fmt.Sprintf("/api/2/apps/%v/rules", appID), nil, &rules)
+	return rules, err
+}
+
+// https://developers.onelogin.com/api-docs/2/app-rules/create-rule
+func (c *Client) CreateAppRule(appID int, rule *AppRule) (*AppRule, error) {
+	if rule.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+	if rule.Match == "" {
+		return nil, ErrMissingField{"match"}
+	}
+
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var created struct {
+		ID int `json:"id"`
+	}
+	err = c.exec(POST, fmt.Sprintf("/api/2/apps/%v/rules", appID), bytes.NewReader(body), &created)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = created.ID
+	return rule, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/app-rules/delete-rule
+func (c *Client) DeleteAppRule(appID, ruleID int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/apps/%v/rules/%v", appID, ruleID), nil, nil)
+}
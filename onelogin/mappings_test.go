@@ -0,0 +1,18 @@
+package onelogin
+
+func (s *OneLoginTestSuite) Test_ListMappings() {
+	for _, enabled := range []bool{true, false} {
+		mappings, err := s.client.ListMappings(enabled)
+		s.Require().NoError(err)
+		for _, mapping := range mappings {
+			s.Equal(enabled, mapping.Enabled)
+		}
+	}
+}
+
+func (s *OneLoginTestSuite) Test_MappingMissingFields() {
+	_, err := s.client.CreateMapping(&Mapping{Match: "all"})
+	s.Equal(ErrMissingField{"name"}, err)
+	_, err = s.client.CreateAppRule(1, &AppRule{Name: "rule"})
+	s.Equal(ErrMissingField{"match"}, err)
+}
@@ -0,0 +1,35 @@
+package onelogin
+
+func (s *OneLoginTestSuite) Test_OIDCAppOperations() {
+	app, err := s.client.CreateOIDCApp(&App{
+		Name: "test_oidc_app",
+		Configuration: &Configuration{
+			RedirectURI:                  "https://example.com/callback",
+			AccessTokenExpirationMinutes: 5,
+		},
+	})
+	s.Require().NoError(err)
+	s.NotZero(app.ID)
+	s.Equal(OIDCConnectorID, app.ConnectorID)
+	s.Require().NotNil(app.SSO)
+	s.NotEmpty(app.SSO.ClientID)
+	s.NotEmpty(app.SSO.ClientSecret)
+
+	sso, err := s.client.RegenerateClientSecret(app.ID)
+	s.Require().NoError(err)
+	s.NotEqual(app.SSO.ClientSecret, sso.ClientSecret)
+
+	err = s.client.DeleteApp(app.ID)
+	s.NoError(err)
+}
+
+func (s *OneLoginTestSuite) Test_CreateOIDCApp_missing_fields() {
+	_, err := s.client.CreateOIDCApp(&App{})
+	s.Equal(ErrMissingField{"name"}, err)
+
+	_, err = s.client.CreateOIDCApp(&App{Name: "test"})
+	s.Equal(ErrMissingField{"configuration.redirect_uri"}, err)
+
+	_, err = s.client.GetOIDCAppToken(&OIDCTokenRequest{})
+	s.Equal(ErrMissingField{"client_id"}, err)
+}
@@ -0,0 +1,772 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ghaggin/onelogin-go-client/internal/yamljson"
+)
+
+// DesiredState is the declared configuration of a tenant.  Apps and
+// roles are matched to the live tenant by name, users by username.  A
+// declared or referenced name shared by several live resources is an
+// error.
+//
+// Only the fields that are set are managed, zero values are left as they
+// are in the tenant.  A role's Apps, Users and Admins are only managed
+// when present, an explicit empty list removes every member.
+type DesiredState struct {
+	Apps  []*App         `json:"apps,omitempty"`
+	Roles []*DesiredRole `json:"roles,omitempty"`
+	Users []*User        `json:"users,omitempty"`
+}
+
+// DesiredRole is a role whose apps are referenced by app name and whose
+// users and admins are referenced by username, so that roles can refer
+// to apps and users created by the same plan
+type DesiredRole struct {
+	Name   string   `json:"name"`
+	Apps   []string `json:"apps"`
+	Users  []string `json:"users"`
+	Admins []string `json:"admins"`
+}
+
+// LoadDesiredState reads and merges desired state files.  Files are
+// YAML or JSON and use the same field names as the API.
+func LoadDesiredState(paths ...string) (*DesiredState, error) {
+	state := &DesiredState{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		s, err := ParseDesiredState(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		state.Apps = append(state.Apps, s.Apps...)
+		state.Roles = append(state.Roles, s.Roles...)
+		state.Users = append(state.Users, s.Users...)
+	}
+	return state, nil
+}
+
+// ParseDesiredState parses a YAML or JSON desired state document.
+// Unknown fields are rejected, a misspelled key would otherwise leave a
+// resource undeclared and plan its deletion.
+func ParseDesiredState(data []byte) (*DesiredState, error) {
+	jsonData, err := yamljson.Convert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	var state DesiredState
+	if err := decoder.Decode(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// PlanAction is the change a plan makes to a resource
+type PlanAction string
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanUpdate PlanAction = "update"
+	PlanDelete PlanAction = "delete"
+)
+
+// ResourceKind is the type of resource a plan or snapshot refers to
+type ResourceKind string
+
+const (
+	ResourceApp             ResourceKind = "app"
+	ResourceRole            ResourceKind = "role"
+	ResourceUser            ResourceKind = "user"
+	ResourceMapping         ResourceKind = "mapping"
+	ResourceAppRule         ResourceKind = "app_rule"
+	ResourceCustomAttribute ResourceKind = "custom_attribute"
+)
+
+// PlanOptions controls which live resources missing from the desired
+// state are deleted.  Nothing is deleted by default.
+type PlanOptions struct {
+	PruneApps  bool
+	PruneRoles bool
+	PruneUsers bool
+}
+
+// FieldDiff is a field changed by a plan.  Old is nil for creates.
+type FieldDiff struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// PlanChange is a single create, update or delete.  ID is the id of the
+// live resource for updates and deletes.
+type PlanChange struct {
+	Action PlanAction
+	Kind   ResourceKind
+	Name   string
+	ID     int
+	Diff   []FieldDiff
+
+	app      *App
+	role     *DesiredRole
+	liveRole *Role
+	user     *User
+}
+
+// Plan is the ordered list of changes needed to reach a desired state.
+// Creates and updates come first with apps and users before the roles
+// that reference them, followed by deletes in the reverse order.
+type Plan struct {
+	Changes []*PlanChange
+
+	appIDs  map[string]int
+	userIDs map[string]int
+}
+
+// Empty reports whether the tenant already matches the desired state
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String formats the plan as a readable diff
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+
+	var b strings.Builder
+	for _, change := range p.Changes {
+		switch change.Action {
+		case PlanCreate:
+			fmt.Fprintf(&b, "+ %s %q\n", change.Kind, change.Name)
+		case PlanUpdate:
+			fmt.Fprintf(&b, "~ %s %q (id %v)\n", change.Kind, change.Name, change.ID)
+		case PlanDelete:
+			fmt.Fprintf(&b, "- %s %q (id %v)\n", change.Kind, change.Name, change.ID)
+		}
+		for _, diff := range change.Diff {
+			if change.Action == PlanCreate {
+				fmt.Fprintf(&b, "    + %s: %s\n", diff.Field, formatDiffValue(diff.New))
+			} else {
+				fmt.Fprintf(&b, "    ~ %s: %s -> %s\n", diff.Field, formatDiffValue(diff.Old), formatDiffValue(diff.New))
+			}
+		}
+	}
+
+	var creates, updates, deletes int
+	for _, change := range p.Changes {
+		switch change.Action {
+		case PlanCreate:
+			creates++
+		case PlanUpdate:
+			updates++
+		case PlanDelete:
+			deletes++
+		}
+	}
+	fmt.Fprintf(&b, "%d to create, %d to update, %d to delete\n", creates, updates, deletes)
+	return b.String()
+}
+
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// liveState is the part of the tenant a plan is computed against.  Apps
+// and roles named in the desired state are read in full, the others only
+// have their id and name.
+type liveState struct {
+	Apps  []*App
+	Roles []*Role
+	Users []*User
+}
+
+// Plan reads the live tenant and computes the changes needed to reach
+// the desired state
+func (c *Client) Plan(desired *DesiredState, opts *PlanOptions) (*Plan, error) {
+	if opts == nil {
+		opts = &PlanOptions{}
+	}
+	live, err := c.fetchLiveState(desired, opts)
+	if err != nil {
+		return nil, err
+	}
+	return computePlan(desired, live, opts)
+}
+
+// liveKinds reports which kinds of live resources a plan reads: those
+// declared, pruned or referenced by a declared role
+func liveKinds(desired *DesiredState, opts *PlanOptions) (apps, roles, users bool) {
+	apps = len(desired.Apps) > 0 || opts.PruneApps
+	roles = len(desired.Roles) > 0 || opts.PruneRoles
+	users = len(desired.Users) > 0 || opts.PruneUsers
+	for _, role := range desired.Roles {
+		apps = apps || role.Apps != nil
+		users = users || role.Users != nil || role.Admins != nil
+	}
+	return apps, roles, users
+}
+
+func (c *Client) fetchLiveState(desired *DesiredState, opts *PlanOptions) (*liveState, error) {
+	needApps, needRoles, needUsers := liveKinds(desired, opts)
+	live := &liveState{}
+
+	appNames := map[string]bool{}
+	for _, app := range desired.Apps {
+		appNames[app.Name] = true
+	}
+	roleNames := map[string]bool{}
+	for _, role := range desired.Roles {
+		roleNames[role.Name] = true
+	}
+
+	if needApps {
+		summaries, err := listAll(func(paging Paging) ([]*AppQueryResponse, error) {
+			return c.ListApps(&AppQuery{Paging: paging})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range summaries {
+			app := &App{ID: summary.ID, Name: summary.Name}
+			if appNames[summary.Name] {
+				app, err = c.GetApp(summary.ID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			live.Apps = append(live.Apps, app)
+		}
+	}
+
+	if needRoles {
+		roles, err := listAll(func(paging Paging) ([]*Role, error) {
+			return c.ListRoles(&RoleQuery{Paging: paging})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			if roleNames[role.Name] {
+				role, err = c.GetRole(role.ID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			live.Roles = append(live.Roles, role)
+		}
+	}
+
+	if needUsers {
+		users, err := listAll(func(paging Paging) ([]*User, error) {
+			return c.ListUsers(&UserQuery{Paging: paging})
+		})
+		if err != nil {
+			return nil, err
+		}
+		live.Users = users
+	}
+	return live, nil
+}
+
+// fields that are read only, set once or managed through roles
+var (
+	appPlanIgnored  = []string{"id", "created_at", "updated_at", "sso", "role_ids"}
+	userPlanIgnored = []string{
+		"id", "password", "password_confirmation", "password_algorithm", "salt", "role_ids",
+		"created_at", "updated_at", "activated_at", "last_login", "password_changed_at",
+		"locked_until", "invitation_sent_at", "invalid_login_attempts",
+	}
+)
+
+func computePlan(desired *DesiredState, live *liveState, opts *PlanOptions) (*Plan, error) {
+	if opts == nil {
+		opts = &PlanOptions{}
+	}
+
+	// names shared by several live resources are only an error when the
+	// desired state declares or references them
+	counts := map[ResourceKind]map[string]int{ResourceApp: {}, ResourceRole: {}, ResourceUser: {}}
+	unambiguous := func(kind ResourceKind, names ...string) error {
+		for _, name := range names {
+			if n := counts[kind][name]; n > 1 {
+				return fmt.Errorf("%d live %ss are named %q", n, kind, name)
+			}
+		}
+		return nil
+	}
+
+	plan := &Plan{appIDs: map[string]int{}, userIDs: map[string]int{}}
+	liveApps := map[string]*App{}
+	appNames := map[int]string{}
+	for _, app := range live.Apps {
+		counts[ResourceApp][app.Name]++
+		liveApps[app.Name] = app
+		plan.appIDs[app.Name] = app.ID
+		appNames[app.ID] = app.Name
+	}
+	liveUsers := map[string]*User{}
+	userNames := map[int]string{}
+	for _, user := range live.Users {
+		counts[ResourceUser][user.UserName]++
+		liveUsers[user.UserName] = user
+		plan.userIDs[user.UserName] = user.ID
+		userNames[user.ID] = user.UserName
+	}
+	liveRoles := map[string]*Role{}
+	for _, role := range live.Roles {
+		counts[ResourceRole][role.Name]++
+		liveRoles[role.Name] = role
+	}
+
+	var deletes []*PlanChange
+
+	// apps
+	seen := map[string]bool{}
+	for _, app := range desired.Apps {
+		if app.Name == "" {
+			return nil, ErrMissingField{"apps.name"}
+		}
+		if seen[app.Name] {
+			return nil, fmt.Errorf("app %q is declared more than once", app.Name)
+		}
+		seen[app.Name] = true
+		if err := unambiguous(ResourceApp, app.Name); err != nil {
+			return nil, err
+		}
+
+		current, ok := liveApps[app.Name]
+		if !ok {
+			diff, err := diffFields(app, nil, appPlanIgnored)
+			if err != nil {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, &PlanChange{Action: PlanCreate, Kind: ResourceApp, Name: app.Name, Diff: diff, app: app})
+			continue
+		}
+		diff, err := diffFields(app, current, appPlanIgnored)
+		if err != nil {
+			return nil, err
+		}
+		if len(diff) > 0 {
+			merged, err := mergeApp(current, app)
+			if err != nil {
+				return nil, fmt.Errorf("app %q: %w", app.Name, err)
+			}
+			plan.Changes = append(plan.Changes, &PlanChange{Action: PlanUpdate, Kind: ResourceApp, Name: app.Name, ID: current.ID, Diff: diff, app: merged})
+		}
+	}
+	if opts.PruneApps {
+		for _, app := range live.Apps {
+			if !seen[app.Name] {
+				deletes = append(deletes, &PlanChange{Action: PlanDelete, Kind: ResourceApp, Name: app.Name, ID: app.ID})
+			}
+		}
+	}
+
+	// users
+	seen = map[string]bool{}
+	for _, user := range desired.Users {
+		if user.UserName == "" {
+			return nil, ErrMissingField{"users.username"}
+		}
+		if seen[user.UserName] {
+			return nil, fmt.Errorf("user %q is declared more than once", user.UserName)
+		}
+		seen[user.UserName] = true
+		if err := unambiguous(ResourceUser, user.UserName); err != nil {
+			return nil, err
+		}
+
+		current, ok := liveUsers[user.UserName]
+		if !ok {
+			diff, err := diffFields(user, nil, userPlanIgnored)
+			if err != nil {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, &PlanChange{Action: PlanCreate, Kind: ResourceUser, Name: user.UserName, Diff: diff, user: user})
+			continue
+		}
+		diff, err := diffFields(user, current, userPlanIgnored)
+		if err != nil {
+			return nil, err
+		}
+		if len(diff) > 0 {
+			update := *user
+			update.ID = current.ID
+			update.Password = ""
+			update.PasswordConfirmation = ""
+			plan.Changes = append(plan.Changes, &PlanChange{Action: PlanUpdate, Kind: ResourceUser, Name: user.UserName, ID: current.ID, Diff: diff, user: &update})
+		}
+	}
+	var userDeletes []*PlanChange
+	if opts.PruneUsers {
+		for _, user := range live.Users {
+			if !seen[user.UserName] {
+				userDeletes = append(userDeletes, &PlanChange{Action: PlanDelete, Kind: ResourceUser, Name: user.UserName, ID: user.ID})
+			}
+		}
+	}
+
+	// roles
+	seen = map[string]bool{}
+	for _, role := range desired.Roles {
+		if role.Name == "" {
+			return nil, ErrMissingField{"roles.name"}
+		}
+		if seen[role.Name] {
+			return nil, fmt.Errorf("role %q is declared more than once", role.Name)
+		}
+		seen[role.Name] = true
+		if err := unambiguous(ResourceRole, role.Name); err != nil {
+			return nil, err
+		}
+		if err := unambiguous(ResourceApp, role.Apps...); err != nil {
+			return nil, fmt.Errorf("role %q: %w", role.Name, err)
+		}
+		if err := unambiguous(ResourceUser, append(slices.Clone(role.Users), role.Admins...)...); err != nil {
+			return nil, fmt.Errorf("role %q: %w", role.Name, err)
+		}
+
+		if err := checkReferences(role, plan.appIDs, plan.userIDs, desired); err != nil {
+			return nil, err
+		}
+
+		current, ok := liveRoles[role.Name]
+		if !ok {
+			var diff []FieldDiff
+			for _, field := range []struct {
+				name  string
+				names []string
+			}{{"apps", role.Apps}, {"users", role.Users}, {"admins", role.Admins}} {
+				if field.names != nil {
+					diff = append(diff, FieldDiff{Field: field.name, New: sortedNames(field.names)})
+				}
+			}
+			plan.Changes = append(plan.Changes, &PlanChange{Action: PlanCreate, Kind: ResourceRole, Name: role.Name, Diff: diff, role: role})
+			continue
+		}
+
+		var diff []FieldDiff
+		for _, field := range []struct {
+			name    string
+			desired []string
+			live    []int
+			names   map[int]string
+		}{
+			{"apps", role.Apps, current.Apps, appNames},
+			{"users", role.Users, current.Users, userNames},
+			{"admins", role.Admins, current.Admins, userNames},
+		} {
+			if field.desired == nil {
+				continue
+			}
+			old := make([]string, 0, len(field.live))
+			for _, id := range field.live {
+				name, ok := field.names[id]
+				if !ok {
+					name = fmt.Sprintf("#%v", id)
+				}
+				old = append(old, name)
+			}
+			old, want := sortedNames(old), sortedNames(field.desired)
+			if !slices.Equal(old, want) {
+				diff = append(diff, FieldDiff{Field: field.name, Old: old, New: want})
+			}
+		}
+		if len(diff) > 0 {
+			plan.Changes = append(plan.Changes, &PlanChange{Action: PlanUpdate, Kind: ResourceRole, Name: role.Name, ID: current.ID, Diff: diff, role: role, liveRole: current})
+		}
+	}
+	if opts.PruneRoles {
+		for _, role := range live.Roles {
+			if !seen[role.Name] {
+				plan.Changes = append(plan.Changes, &PlanChange{Action: PlanDelete, Kind: ResourceRole, Name: role.Name, ID: role.ID})
+			}
+		}
+	}
+
+	// roles are deleted before the users and apps they reference
+	plan.Changes = append(plan.Changes, userDeletes...)
+	plan.Changes = append(plan.Changes, deletes...)
+	return plan, nil
+}
+
+// checkReferences verifies that the apps and users of a role exist in
+// the tenant or are declared in the desired state
+func checkReferences(role *DesiredRole, appIDs, userIDs map[string]int, desired *DesiredState) error {
+	for _, name := range role.Apps {
+		if _, ok := appIDs[name]; ok {
+			continue
+		}
+		if !slices.ContainsFunc(desired.Apps, func(app *App) bool { return app.Name == name }) {
+			return fmt.Errorf("role %q references unknown app %q", role.Name, name)
+		}
+	}
+	for _, name := range append(slices.Clone(role.Users), role.Admins...) {
+		if _, ok := userIDs[name]; ok {
+			continue
+		}
+		if !slices.ContainsFunc(desired.Users, func(user *User) bool { return user.UserName == name }) {
+			return fmt.Errorf("role %q references unknown user %q", role.Name, name)
+		}
+	}
+	return nil
+}
+
+func sortedNames(names []string) []string {
+	sorted := slices.Clone(names)
+	if sorted == nil {
+		sorted = []string{}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// diffFields compares the fields set in desired to live.  Nested objects
+// are compared field by field, lists are compared as a whole.  When live
+// is nil every set field is returned.
+func diffFields(desired, live interface{}, ignored []string) ([]FieldDiff, error) {
+	want, err := toJSONMap(desired)
+	if err != nil {
+		return nil, err
+	}
+	var have map[string]interface{}
+	if live != nil && !reflect.ValueOf(live).IsNil() {
+		have, err = toJSONMap(live)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range ignored {
+		delete(want, field)
+	}
+
+	var diff []FieldDiff
+	flattenSet("", want, func(field string, value interface{}) {
+		if have == nil {
+			diff = append(diff, FieldDiff{Field: field, New: value})
+			return
+		}
+		current := lookupField(have, field)
+		if !reflect.DeepEqual(current, value) {
+			diff = append(diff, FieldDiff{Field: field, Old: current, New: value})
+		}
+	})
+	return diff, nil
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// flattenSet calls fn with the dotted path of every leaf that is not a
+// zero value, in key order
+func flattenSet(prefix string, m map[string]interface{}, fn func(field string, value interface{})) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		switch value := m[key].(type) {
+		case map[string]interface{}:
+			flattenSet(field, value, fn)
+		case nil, bool, float64, string:
+			if value != nil && !reflect.ValueOf(value).IsZero() {
+				fn(field, value)
+			}
+		default:
+			fn(field, value)
+		}
+	}
+}
+
+func lookupField(m map[string]interface{}, field string) interface{} {
+	var current interface{} = m
+	for _, key := range strings.Split(field, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[key]
+	}
+	return current
+}
+
+// mergeApp overlays the fields set in desired onto the live app, since
+// UpdateApp replaces the whole app.  It fails rather than fall back to
+// desired, whose update would clear the fields it does not set.
+func mergeApp(live, desired *App) (*App, error) {
+	base, err := toJSONMap(live)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := toJSONMap(desired)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range appPlanIgnored {
+		delete(overlay, field)
+		if field != "id" {
+			delete(base, field)
+		}
+	}
+	flattenSet("", overlay, func(field string, value interface{}) {
+		keys := strings.Split(field, ".")
+		object := base
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := object[key].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				object[key] = next
+			}
+			object = next
+		}
+		object[keys[len(keys)-1]] = value
+	})
+
+	data, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	var merged App
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// ApplyPlan makes the changes of a plan in order, stopping at the first
+// error.  Ids of created apps and users are used to resolve the roles
+// that reference them.
+func (c *Client) ApplyPlan(plan *Plan) error {
+	for _, change := range plan.Changes {
+		if err := c.applyChange(plan, change); err != nil {
+			return fmt.Errorf("%s %s %q: %w", change.Action, change.Kind, change.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) applyChange(plan *Plan, change *PlanChange) error {
+	switch change.Kind {
+	case ResourceApp:
+		switch change.Action {
+		case PlanCreate:
+			app := *change.app
+			created, err := c.CreateApp(&app)
+			if err != nil {
+				return err
+			}
+			plan.appIDs[change.Name] = created.ID
+			return nil
+		case PlanUpdate:
+			return c.UpdateApp(change.app)
+		case PlanDelete:
+			return c.DeleteApp(change.ID)
+		}
+
+	case ResourceUser:
+		switch change.Action {
+		case PlanCreate:
+			user := *change.user
+			created, err := c.CreateUser(&user)
+			if err != nil {
+				return err
+			}
+			plan.userIDs[change.Name] = created.ID
+			return nil
+		case PlanUpdate:
+			_, err := c.UpdateUser(change.user)
+			return err
+		case PlanDelete:
+			return c.DeleteUser(change.ID)
+		}
+
+	case ResourceRole:
+		switch change.Action {
+		case PlanCreate:
+			created, err := c.CreateRole(&Role{Name: change.Name})
+			if err != nil {
+				return err
+			}
+			change.ID = created.ID
+			change.liveRole = &Role{ID: created.ID, Name: change.Name}
+			fallthrough
+		case PlanUpdate:
+			role, err := plan.resolveRole(change)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateRole(role)
+			return err
+		case PlanDelete:
+			return c.DeleteRole(change.ID)
+		}
+	}
+	return fmt.Errorf("unsupported change")
+}
+
+// resolveRole converts a desired role to ids, keeping the live members
+// of lists the desired role does not manage
+func (p *Plan) resolveRole(change *PlanChange) (*Role, error) {
+	resolve := func(names []string, ids map[string]int, live []int) ([]int, error) {
+		if names == nil {
+			return live, nil
+		}
+		resolved := make([]int, 0, len(names))
+		for _, name := range names {
+			id, ok := ids[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown %q", name)
+			}
+			resolved = append(resolved, id)
+		}
+		return resolved, nil
+	}
+
+	role := &Role{ID: change.ID, Name: change.Name}
+	var err error
+	if role.Apps, err = resolve(change.role.Apps, p.appIDs, change.liveRole.Apps); err != nil {
+		return nil, err
+	}
+	if role.Users, err = resolve(change.role.Users, p.userIDs, change.liveRole.Users); err != nil {
+		return nil, err
+	}
+	if role.Admins, err = resolve(change.role.Admins, p.userIDs, change.liveRole.Admins); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
@@ -0,0 +1,215 @@
+package onelogin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDesiredState(t *testing.T) {
+	state, err := ParseDesiredState([]byte(`
+apps:
+  - name: Intranet
+    connector_id: 110016
+    auth_method: saml
+    configuration:
+      signature_algorithm: SHA-256
+users:
+  - username: alice
+    email: alice@example.com
+roles:
+  - name: Staff
+    apps: [Intranet]
+    users: [alice]
+`))
+	require.NoError(t, err)
+	require.Len(t, state.Apps, 1)
+	require.Equal(t, AppAuthMethodSAML, state.Apps[0].AuthMethod)
+	require.Equal(t, "SHA-256", state.Apps[0].Configuration.SignatureAlgorithm)
+	require.Equal(t, "alice", state.Users[0].UserName)
+	require.Equal(t, []string{"Intranet"}, state.Roles[0].Apps)
+	require.Nil(t, state.Roles[0].Admins)
+
+	// JSON is valid YAML
+	state, err = ParseDesiredState([]byte(`{"roles": [{"name": "Empty", "users": []}]}`))
+	require.NoError(t, err)
+	require.NotNil(t, state.Roles[0].Users)
+	require.Empty(t, state.Roles[0].Users)
+
+	// a misspelled key is an error rather than an empty list of roles
+	_, err = ParseDesiredState([]byte("role:\n  - name: Staff\n"))
+	require.ErrorContains(t, err, `unknown field "role"`)
+	_, err = ParseDesiredState([]byte("users:\n  - usename: alice\n"))
+	require.ErrorContains(t, err, `unknown field "usename"`)
+}
+
+func TestLoadDesiredState(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "apps.yaml"), []byte("apps:\n  - name: A\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.json"), []byte(`{"users": [{"username": "u"}]}`), 0o600))
+
+	state, err := LoadDesiredState(filepath.Join(dir, "apps.yaml"), filepath.Join(dir, "users.json"))
+	require.NoError(t, err)
+	require.Len(t, state.Apps, 1)
+	require.Len(t, state.Users, 1)
+
+	_, err = LoadDesiredState(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func testLiveState() *liveState {
+	return &liveState{
+		Apps: []*App{
+			{ID: 1, Name: "Intranet", ConnectorID: 110016, Description: "old", Configuration: &Configuration{SignatureAlgorithm: "SHA-1", LoginURL: "https://intranet"}},
+			{ID: 2, Name: "Legacy"},
+		},
+		Users: []*User{
+			{ID: 10, UserName: "alice", Email: "alice@example.com", Title: "Engineer"},
+			{ID: 11, UserName: "bob", Email: "bob@example.com"},
+		},
+		Roles: []*Role{
+			{ID: 20, Name: "Staff", Apps: []int{1}, Users: []int{11}, Admins: []int{10}},
+			{ID: 21, Name: "Old"},
+		},
+	}
+}
+
+func TestComputePlan(t *testing.T) {
+	desired := &DesiredState{
+		Apps: []*App{
+			{Name: "Intranet", ConnectorID: 110016, Description: "new", Configuration: &Configuration{SignatureAlgorithm: "SHA-1"}},
+			{Name: "Wiki", ConnectorID: 110016},
+		},
+		Users: []*User{
+			{UserName: "alice", Email: "alice@example.com", Title: "Engineer", Password: "ignored"},
+			{UserName: "carol", Email: "carol@example.com"},
+		},
+		Roles: []*DesiredRole{
+			{Name: "Staff", Apps: []string{"Intranet", "Wiki"}, Users: []string{"bob", "carol"}},
+		},
+	}
+
+	plan, err := computePlan(desired, testLiveState(), nil)
+	require.NoError(t, err)
+
+	type change struct {
+		Action PlanAction
+		Kind   ResourceKind
+		Name   string
+		ID     int
+	}
+	var changes []change
+	for _, c := range plan.Changes {
+		changes = append(changes, change{c.Action, c.Kind, c.Name, c.ID})
+	}
+	require.Equal(t, []change{
+		{PlanUpdate, ResourceApp, "Intranet", 1},
+		{PlanCreate, ResourceApp, "Wiki", 0},
+		{PlanCreate, ResourceUser, "carol", 0},
+		{PlanUpdate, ResourceRole, "Staff", 20},
+	}, changes)
+
+	require.Equal(t, []FieldDiff{{Field: "description", Old: "old", New: "new"}}, plan.Changes[0].Diff)
+	require.Equal(t, []FieldDiff{
+		{Field: "apps", Old: []string{"Intranet"}, New: []string{"Intranet", "Wiki"}},
+		{Field: "users", Old: []string{"bob"}, New: []string{"bob", "carol"}},
+	}, plan.Changes[3].Diff)
+
+	// the update keeps the live fields that are not declared
+	merged := plan.Changes[0].app
+	require.Equal(t, 1, merged.ID)
+	require.Equal(t, "new", merged.Description)
+	require.Equal(t, "https://intranet", merged.Configuration.LoginURL)
+
+	require.Equal(t, `~ app "Intranet" (id 1)
+    ~ description: "old" -> "new"
++ app "Wiki"
+    + connector_id: 110016
+    + name: "Wiki"
++ user "carol"
+    + email: "carol@example.com"
+    + username: "carol"
+~ role "Staff" (id 20)
+    ~ apps: ["Intranet"] -> ["Intranet","Wiki"]
+    ~ users: ["bob"] -> ["bob","carol"]
+2 to create, 2 to update, 0 to delete
+`, plan.String())
+
+	// ids of created resources resolve the role
+	plan.appIDs["Wiki"] = 3
+	plan.userIDs["carol"] = 12
+	role, err := plan.resolveRole(plan.Changes[3])
+	require.NoError(t, err)
+	require.Equal(t, &Role{ID: 20, Name: "Staff", Apps: []int{1, 3}, Users: []int{11, 12}, Admins: []int{10}}, role)
+}
+
+func TestComputePlan_prune(t *testing.T) {
+	desired := &DesiredState{
+		Apps:  []*App{{Name: "Intranet"}},
+		Users: []*User{{UserName: "alice"}},
+		Roles: []*DesiredRole{{Name: "Staff"}},
+	}
+
+	plan, err := computePlan(desired, testLiveState(), &PlanOptions{PruneApps: true, PruneRoles: true, PruneUsers: true})
+	require.NoError(t, err)
+
+	var deletes []string
+	for _, c := range plan.Changes {
+		require.Equal(t, PlanDelete, c.Action)
+		deletes = append(deletes, string(c.Kind)+":"+c.Name)
+	}
+	require.Equal(t, []string{"role:Old", "user:bob", "app:Legacy"}, deletes)
+
+	plan, err = computePlan(desired, testLiveState(), nil)
+	require.NoError(t, err)
+	require.True(t, plan.Empty())
+	require.Equal(t, "no changes\n", plan.String())
+}
+
+func TestComputePlan_invalid(t *testing.T) {
+	_, err := computePlan(&DesiredState{Apps: []*App{{Name: "A"}, {Name: "A"}}}, &liveState{}, nil)
+	require.Error(t, err)
+
+	_, err = computePlan(&DesiredState{Users: []*User{{Email: "a@example.com"}}}, &liveState{}, nil)
+	require.Equal(t, ErrMissingField{"users.username"}, err)
+
+	_, err = computePlan(&DesiredState{Roles: []*DesiredRole{{Name: "R", Apps: []string{"Missing"}}}}, &liveState{}, nil)
+	require.ErrorContains(t, err, `unknown app "Missing"`)
+}
+
+func TestComputePlan_ambiguous(t *testing.T) {
+	live := &liveState{
+		Apps:  []*App{{ID: 1, Name: "A"}, {ID: 2, Name: "A"}},
+		Roles: []*Role{{ID: 3, Name: "R"}, {ID: 4, Name: "R"}},
+		Users: []*User{{ID: 5, UserName: "u"}, {ID: 6, UserName: "u"}},
+	}
+
+	_, err := computePlan(&DesiredState{Apps: []*App{{Name: "A"}}}, live, nil)
+	require.EqualError(t, err, `2 live apps are named "A"`)
+
+	_, err = computePlan(&DesiredState{Users: []*User{{UserName: "u"}}}, live, nil)
+	require.EqualError(t, err, `2 live users are named "u"`)
+
+	_, err = computePlan(&DesiredState{Roles: []*DesiredRole{{Name: "R"}}}, live, nil)
+	require.EqualError(t, err, `2 live roles are named "R"`)
+
+	_, err = computePlan(&DesiredState{Roles: []*DesiredRole{{Name: "New", Admins: []string{"u"}}}}, live, nil)
+	require.EqualError(t, err, `role "New": 2 live users are named "u"`)
+
+	// duplicates the desired state does not use are left alone
+	_, err = computePlan(&DesiredState{Apps: []*App{{Name: "B"}}}, live, nil)
+	require.NoError(t, err)
+}
+
+func TestLiveKinds(t *testing.T) {
+	apps, roles, users := liveKinds(&DesiredState{Apps: []*App{{Name: "A"}}}, &PlanOptions{})
+	require.Equal(t, []bool{true, false, false}, []bool{apps, roles, users})
+
+	apps, roles, users = liveKinds(&DesiredState{Roles: []*DesiredRole{{Name: "R", Users: []string{}}}}, &PlanOptions{})
+	require.Equal(t, []bool{false, true, true}, []bool{apps, roles, users})
+
+	apps, roles, users = liveKinds(&DesiredState{}, &PlanOptions{PruneApps: true})
+	require.Equal(t, []bool{true, false, false}, []bool{apps, roles, users})
+}
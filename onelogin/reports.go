@@ -0,0 +1,233 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Report is one of the account's built in or custom reports
+// https://developers.onelogin.com/api-docs/1/reports/get-reports
+type Report struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// ReportQuery holds the parameters for running a report.  Paging.Cursor
+// is sent as the after_cursor, Params are passed through as the
+// report's own query parameters.
+type ReportQuery struct {
+	Paging
+	Params map[string]string
+}
+
+// https://developers.onelogin.com/api-docs/1/reports/get-reports
+func (c *Client) ListReports(paging *Paging) ([]*Report, *Pagination, error) {
+	var reports []*Report
+	resp := v1Response{Data: &reports}
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        "/api/1/reports",
+		queryParams: v1PagingParams(map[string]string{}, paging),
+		respModel:   &resp,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Pagination == nil {
+		resp.Pagination = &Pagination{}
+	}
+	return reports, resp.Pagination, nil
+}
+
+// https://developers.onelogin.com/api-docs/1/reports/get-report-by-id
+func (c *Client) GetReport(id int) (*Report, error) {
+	var reports []*Report
+	err := c.execRequest(&oneloginRequest{
+		method:    GET,
+		path:      fmt.Sprintf("/api/1/reports/%v", id),
+		respModel: &v1Response{Data: &reports},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("report %v not found", id)
+	}
+	return reports[0], nil
+}
+
+// RunReport runs a report and returns one page of rows.  Rows are
+// returned raw since their columns depend on the report, decode them
+// with DecodeReportRows or write them out with WriteReportCSV.
+//
+// https://developers.onelogin.com/api-docs/1/reports/run-report
+func (c *Client) RunReport(id int, query *ReportQuery) ([]json.RawMessage, *Pagination, error) {
+	params := map[string]string{}
+	for key, value := range query.Params {
+		params[key] = value
+	}
+
+	var rows []json.RawMessage
+	resp := v1Response{Data: &rows}
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        fmt.Sprintf("/api/1/reports/%v/run", id),
+		queryParams: v1PagingParams(params, &query.Paging),
+		respModel:   &resp,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Pagination == nil {
+		resp.Pagination = &Pagination{}
+	}
+	return rows, resp.Pagination, nil
+}
+
+// EachReportRow runs a report and calls fn for every row, following the
+// cursors until the last page or until fn returns an error
+func (c *Client) EachReportRow(id int, params map[string]string, fn func(row json.RawMessage) error) error {
+	query := &ReportQuery{Params: params}
+	for {
+		rows, pagination, err := c.RunReport(id, query)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+
+		if pagination.AfterCursor == "" || len(rows) == 0 {
+			return nil
+		}
+		query.Cursor = pagination.AfterCursor
+	}
+}
+
+// DecodeReportRows decodes raw report rows into a struct describing the
+// report's columns
+func DecodeReportRows[T any](rows []json.RawMessage) ([]T, error) {
+	decoded := make([]T, 0, len(rows))
+	for _, row := range rows {
+		var v T
+		if err := json.Unmarshal(row, &v); err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, v)
+	}
+	return decoded, nil
+}
+
+// WriteReportCSV runs a report and streams every row to w as CSV.  The
+// header is taken from the columns of the first row, in the order the
+// API returns them, and the output is flushed after each page.  Since
+// the header is written before later rows are read, a row with a column
+// the first row does not have fails the export rather than losing the
+// column.
+func (c *Client) WriteReportCSV(w io.Writer, id int, params map[string]string) error {
+	writer := csv.NewWriter(w)
+	var columns []string
+
+	query := &ReportQuery{Params: params}
+	for {
+		rows, pagination, err := c.RunReport(id, query)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			record, err := reportRecord(row, &columns, writer)
+			if err != nil {
+				return err
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		if pagination.AfterCursor == "" || len(rows) == 0 {
+			return nil
+		}
+		query.Cursor = pagination.AfterCursor
+	}
+}
+
+// reportRecord converts a row to a CSV record.  The first row sets the
+// columns and writes the header, later rows must not add columns.
+func reportRecord(row json.RawMessage, columns *[]string, writer *csv.Writer) ([]string, error) {
+	keys, values, err := orderedObject(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if *columns == nil {
+		*columns = keys
+		if err := writer.Write(keys); err != nil {
+			return nil, err
+		}
+	}
+
+	record := make([]string, len(*columns))
+	for i, column := range *columns {
+		record[i] = csvValue(values[column])
+		delete(values, column)
+	}
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("report row has column %q that is not in the header", key)
+		}
+	}
+	return record, nil
+}
+
+// orderedObject decodes a JSON object keeping the order of its keys
+func orderedObject(data json.RawMessage) ([]string, map[string]json.RawMessage, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, token.(string))
+
+		// skip the value
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, values, nil
+}
+
+func csvValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,62 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_Reports() {
+	reports, _, err := s.client.ListReports(&Paging{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(reports)
+
+	report, err := s.client.GetReport(reports[0].ID)
+	s.Require().NoError(err)
+	s.Equal(reports[0].ID, report.ID)
+
+	var buf bytes.Buffer
+	err = s.client.WriteReportCSV(&buf, report.ID, nil)
+	s.Require().NoError(err)
+}
+
+func TestDecodeReportRows(t *testing.T) {
+	type row struct {
+		UserID   int    `json:"user_id"`
+		Username string `json:"username"`
+	}
+
+	rows, err := DecodeReportRows[row]([]json.RawMessage{
+		json.RawMessage(`{"user_id": 1, "username": "a"}`),
+		json.RawMessage(`{"user_id": 2, "username": "b"}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []row{{1, "a"}, {2, "b"}}, rows)
+}
+
+func TestReportRecord(t *testing.T) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	var columns []string
+
+	for _, row := range []string{
+		`{"username": "a", "user_id": 1, "last_login": null, "roles": ["x", "y"]}`,
+		`{"user_id": 2, "username": "b,c"}`,
+	} {
+		record, err := reportRecord(json.RawMessage(row), &columns, writer)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(record))
+	}
+	writer.Flush()
+
+	require.Equal(t, "username,user_id,last_login,roles\n"+
+		"a,1,,\"[\"\"x\"\", \"\"y\"\"]\"\n"+
+		"\"b,c\",2,,\n", buf.String())
+
+	// columns missing from the header are not dropped silently
+	_, err := reportRecord(json.RawMessage(`{"user_id": 3, "extra": true}`), &columns, writer)
+	require.ErrorContains(t, err, `"extra"`)
+}
@@ -57,10 +57,15 @@ func (c *Client) GetRole(id int) (*Role, error) {
 }
 
 func (c *Client) UpdateRole(role *Role) (*Role, error) {
-	// get the current state
-	currentRole, err := c.GetRole(role.ID)
-	if err != nil {
-		return nil, err
+	// get the current state, a role created in dry run mode only has the
+	// name it was created with
+	currentRole := &Role{ID: role.ID, Name: role.Name}
+	var err error
+	if !c.dryRunCreated(role.ID) {
+		currentRole, err = c.GetRole(role.ID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// update name
@@ -0,0 +1,122 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+)
+
+type SAMLAssertionRequest struct {
+	UsernameOrEmail string `json:"username_or_email"`
+	Password        string `json:"password"`
+	AppID           int    `json:"app_id,string"`
+	// Subdomain defaults to the subdomain the client is configured with
+	Subdomain string `json:"subdomain"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+type VerifySAMLFactorRequest struct {
+	AppID       int    `json:"app_id,string"`
+	DeviceID    int    `json:"device_id,string"`
+	StateToken  string `json:"state_token"`
+	OTPToken    string `json:"otp_token,omitempty"`
+	DoNotNotify bool   `json:"do_not_notify,omitempty"`
+}
+
+// SAMLAssertionResponse is returned by both GenerateSAMLAssertion and
+// VerifySAMLFactor.  When MFA is required StateToken and Devices are
+// set and the flow is finished with VerifySAMLFactor, otherwise
+// Assertion holds the base64 encoded assertion and DecodedAssertion
+// the assertion XML.
+type SAMLAssertionResponse struct {
+	Message          string       `json:"message,omitempty"`
+	Assertion        string       `json:"data,omitempty"`
+	DecodedAssertion []byte       `json:"-"`
+	StateToken       string       `json:"state_token,omitempty"`
+	Devices          []*MFADevice `json:"devices,omitempty"`
+	CallbackURL      string       `json:"callback_url,omitempty"`
+	User             *FactorUser  `json:"user,omitempty"`
+}
+
+// MFARequired reports whether a factor must be verified before the
+// assertion is issued
+func (r *SAMLAssertionResponse) MFARequired() bool {
+	return r.StateToken != "" && r.Assertion == ""
+}
+
+// MFADevice is an enrolled factor offered when MFA is required
+type MFADevice struct {
+	DeviceID   int    `json:"device_id"`
+	DeviceType string `json:"device_type"`
+}
+
+// FactorUser identifies the user an MFA challenge was issued for
+type FactorUser struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstname"`
+	LastName  string `json:"lastname"`
+}
+
+// https://developers.onelogin.com/api-docs/2/saml-assertions/generate-saml-assertion
+func (c *Client) GenerateSAMLAssertion(request *SAMLAssertionRequest) (*SAMLAssertionResponse, error) {
+	if request.UsernameOrEmail == "" {
+		return nil, ErrMissingField{"username_or_email"}
+	}
+	if request.Password == "" {
+		return nil, ErrMissingField{"password"}
+	}
+	if request.AppID == 0 {
+		return nil, ErrMissingField{"app_id"}
+	}
+
+	r := *request
+	if r.Subdomain == "" {
+		r.Subdomain = c.config.Subdomain
+	}
+
+	return c.samlAssertion("/api/2/saml_assertion", &r)
+}
+
+// https://developers.onelogin.com/api-docs/2/saml-assertions/verify-factor
+func (c *Client) VerifySAMLFactor(request *VerifySAMLFactorRequest) (*SAMLAssertionResponse, error) {
+	if request.AppID == 0 {
+		return nil, ErrMissingField{"app_id"}
+	}
+	if request.DeviceID == 0 {
+		return nil, ErrMissingField{"device_id"}
+	}
+	if request.StateToken == "" {
+		return nil, ErrMissingField{"state_token"}
+	}
+
+	return c.samlAssertion("/api/2/saml_assertion/verify_factor", request)
+}
+
+func (c *Client) samlAssertion(path string, request interface{}) (*SAMLAssertionResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SAMLAssertionResponse
+	err = c.execRequest(&oneloginRequest{
+		method:    POST,
+		path:      path,
+		body:      bytes.NewReader(body),
+		respModel: &resp,
+		readOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Assertion != "" {
+		resp.DecodedAssertion, err = base64.StdEncoding.DecodeString(resp.Assertion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &resp, nil
+}
@@ -0,0 +1,312 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	SAMLBindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	SAMLBindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+
+	NameIDFormatEmail       = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIDFormatTransient   = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+	NameIDFormatPersistent  = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	NameIDFormatUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+)
+
+// nameIDFormatIDs maps NameID formats to the values of the
+// saml_nameid_format_id configuration field
+var nameIDFormatIDs = map[string]string{
+	NameIDFormatEmail:       "0",
+	NameIDFormatTransient:   "1",
+	NameIDFormatPersistent:  "2",
+	NameIDFormatUnspecified: "3",
+}
+
+// SAMLEndpoint is a service location listed in SAML metadata
+type SAMLEndpoint struct {
+	Binding   string `xml:"Binding,attr"`
+	Location  string `xml:"Location,attr"`
+	Index     int    `xml:"index,attr"`
+	IsDefault bool   `xml:"isDefault,attr"`
+}
+
+// SPMetadata is the parts of a service provider's metadata needed to
+// configure a SAML app
+type SPMetadata struct {
+	EntityID                  string
+	AssertionConsumerServices []*SAMLEndpoint
+	SingleLogoutServices      []*SAMLEndpoint
+	NameIDFormats             []string
+	Certificates              []string
+}
+
+// IdPMetadata is OneLogin's identity provider metadata for an app
+type IdPMetadata struct {
+	EntityID             string
+	SingleSignOnServices []*SAMLEndpoint
+	SingleLogoutServices []*SAMLEndpoint
+	NameIDFormats        []string
+
+	// SigningCertificates holds the base64 DER certificates as they
+	// appear in the metadata
+	SigningCertificates []string
+}
+
+type xmlEntitiesDescriptor struct {
+	XMLName  xml.Name               `xml:"EntitiesDescriptor"`
+	Entities []*xmlEntityDescriptor `xml:"EntityDescriptor"`
+}
+
+type xmlEntityDescriptor struct {
+	XMLName  xml.Name          `xml:"EntityDescriptor"`
+	EntityID string            `xml:"entityID,attr"`
+	SP       *xmlSSODescriptor `xml:"SPSSODescriptor"`
+	IdP      *xmlSSODescriptor `xml:"IDPSSODescriptor"`
+}
+
+type xmlSSODescriptor struct {
+	KeyDescriptors            []*xmlKeyDescriptor `xml:"KeyDescriptor"`
+	SingleLogoutServices      []*SAMLEndpoint     `xml:"SingleLogoutService"`
+	NameIDFormats             []string            `xml:"NameIDFormat"`
+	AssertionConsumerServices []*SAMLEndpoint     `xml:"AssertionConsumerService"`
+	SingleSignOnServices      []*SAMLEndpoint     `xml:"SingleSignOnService"`
+}
+
+type xmlKeyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+// ParseSPMetadata reads a service provider's metadata XML
+func ParseSPMetadata(data []byte) (*SPMetadata, error) {
+	entity, err := parseEntityDescriptor(data, func(e *xmlEntityDescriptor) bool { return e.SP != nil })
+	if err != nil {
+		return nil, err
+	}
+	if entity.SP == nil {
+		return nil, fmt.Errorf("metadata for %s has no SPSSODescriptor", entity.EntityID)
+	}
+
+	sp := entity.SP
+	return &SPMetadata{
+		EntityID:                  entity.EntityID,
+		AssertionConsumerServices: sp.AssertionConsumerServices,
+		SingleLogoutServices:      sp.SingleLogoutServices,
+		NameIDFormats:             trimAll(sp.NameIDFormats),
+		Certificates:              keyDescriptorCertificates(sp.KeyDescriptors, ""),
+	}, nil
+}
+
+// ParseSPMetadataFile reads a service provider's metadata XML from a file
+func ParseSPMetadataFile(path string) (*SPMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSPMetadata(data)
+}
+
+// FetchSPMetadata downloads and parses a service provider's metadata
+func (c *Client) FetchSPMetadata(url string) (*SPMetadata, error) {
+	data, err := c.fetchMetadata(url)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSPMetadata(data)
+}
+
+// ParseIdPMetadata reads identity provider metadata XML
+func ParseIdPMetadata(data []byte) (*IdPMetadata, error) {
+	entity, err := parseEntityDescriptor(data, func(e *xmlEntityDescriptor) bool { return e.IdP != nil })
+	if err != nil {
+		return nil, err
+	}
+	if entity.IdP == nil {
+		return nil, fmt.Errorf("metadata for %s has no IDPSSODescriptor", entity.EntityID)
+	}
+
+	idp := entity.IdP
+	return &IdPMetadata{
+		EntityID:             entity.EntityID,
+		SingleSignOnServices: idp.SingleSignOnServices,
+		SingleLogoutServices: idp.SingleLogoutServices,
+		NameIDFormats:        trimAll(idp.NameIDFormats),
+		SigningCertificates:  keyDescriptorCertificates(idp.KeyDescriptors, "signing"),
+	}, nil
+}
+
+// GetAppIdPMetadata fetches OneLogin's metadata for a SAML app from
+// the app's SSO.MetadataURL
+func (c *Client) GetAppIdPMetadata(appID int) (*IdPMetadata, error) {
+	app, err := c.GetApp(appID)
+	if err != nil {
+		return nil, err
+	}
+	if app.SSO == nil || app.SSO.MetadataURL == "" {
+		return nil, ErrMissingField{"sso.metadata_url"}
+	}
+
+	data, err := c.fetchMetadata(app.SSO.MetadataURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIdPMetadata(data)
+}
+
+// AssertionConsumerService picks the ACS endpoint OneLogin should post
+// to: the default endpoint, else the HTTP-POST endpoint with the lowest
+// index, else the first endpoint listed
+func (m *SPMetadata) AssertionConsumerService() *SAMLEndpoint {
+	if len(m.AssertionConsumerServices) == 0 {
+		return nil
+	}
+	for _, acs := range m.AssertionConsumerServices {
+		if acs.IsDefault {
+			return acs
+		}
+	}
+
+	sorted := make([]*SAMLEndpoint, len(m.AssertionConsumerServices))
+	copy(sorted, m.AssertionConsumerServices)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+	for _, acs := range sorted {
+		if acs.Binding == SAMLBindingHTTPPost {
+			return acs
+		}
+	}
+	return sorted[0]
+}
+
+// SingleLogoutService picks the SLS endpoint, preferring HTTP-Redirect
+func (m *SPMetadata) SingleLogoutService() *SAMLEndpoint {
+	for _, sls := range m.SingleLogoutServices {
+		if sls.Binding == SAMLBindingHTTPRedirect {
+			return sls
+		}
+	}
+	if len(m.SingleLogoutServices) > 0 {
+		return m.SingleLogoutServices[0]
+	}
+	return nil
+}
+
+// NewApp builds an app for a SAML connector configured from the
+// metadata, ready to pass to CreateApp
+func (m *SPMetadata) NewApp(connectorID int, name string) (*App, error) {
+	if m.EntityID == "" {
+		return nil, ErrMissingField{"entityID"}
+	}
+	acs := m.AssertionConsumerService()
+	if acs == nil {
+		return nil, ErrMissingField{"AssertionConsumerService"}
+	}
+
+	config := &SAMLConfiguration{
+		Audience:    m.EntityID,
+		ConsumerURL: acs.Location,
+		Recipient:   acs.Location,
+	}
+	if sls := m.SingleLogoutService(); sls != nil {
+		config.LogoutURL = sls.Location
+	}
+	for _, format := range m.NameIDFormats {
+		if _, ok := nameIDFormatIDs[format]; ok {
+			config.NameIDFormat = format
+			break
+		}
+	}
+
+	app := &App{
+		ConnectorID: connectorID,
+		Name:        name,
+	}
+	app.SetConfiguration(config)
+	return app, nil
+}
+
+// maxMetadataSize is the largest metadata document read, in bytes
+const maxMetadataSize = 10 << 20
+
+func (c *Client) fetchMetadata(url string) ([]byte, error) {
+	if err := c.beginRequest(); err != nil {
+		return nil, err
+	}
+	defer c.root().inflight.Done()
+
+	ctx, cancel := context.WithTimeout(c.context(), c.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("metadata request failed with status code %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMetadataSize {
+		return nil, fmt.Errorf("metadata is larger than %d bytes", maxMetadataSize)
+	}
+	return data, nil
+}
+
+// parseEntityDescriptor reads a single EntityDescriptor, or the first
+// matching entity of an EntitiesDescriptor
+func parseEntityDescriptor(data []byte, match func(*xmlEntityDescriptor) bool) (*xmlEntityDescriptor, error) {
+	var entity xmlEntityDescriptor
+	err := xml.Unmarshal(data, &entity)
+	if err == nil {
+		return &entity, nil
+	}
+
+	var entities xmlEntitiesDescriptor
+	if xml.Unmarshal(data, &entities) != nil {
+		return nil, err
+	}
+	for _, e := range entities.Entities {
+		if match(e) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("metadata has no matching EntityDescriptor")
+}
+
+// keyDescriptorCertificates returns the certificates for a key use,
+// key descriptors without a use apply to both signing and encryption
+func keyDescriptorCertificates(keys []*xmlKeyDescriptor, use string) []string {
+	var certs []string
+	for _, key := range keys {
+		if use != "" && key.Use != "" && key.Use != use {
+			continue
+		}
+		for _, cert := range key.Certificates {
+			certs = append(certs, strings.Join(strings.Fields(cert), ""))
+		}
+	}
+	return certs
+}
+
+func trimAll(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, v := range values {
+		trimmed = append(trimmed, strings.TrimSpace(v))
+	}
+	return trimmed
+}
@@ -0,0 +1,98 @@
+package onelogin
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSPMetadata = `<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sp.example.com/saml">
+  <md:SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data><ds:X509Certificate>
+          MIIC
+          AAAA
+        </ds:X509Certificate></ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/slo/post"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://sp.example.com/slo"/>
+    <md:NameIDFormat>
+      urn:oasis:names:tc:SAML:2.0:nameid-format:persistent
+    </md:NameIDFormat>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Artifact" Location="https://sp.example.com/acs/artifact" index="0"/>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/acs" index="1"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`
+
+const testIdPMetadata = `<?xml version="1.0"?>
+<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">
+  <EntityDescriptor entityID="https://app.onelogin.com/saml/metadata/abc">
+    <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+      <KeyDescriptor use="signing">
+        <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+          <ds:X509Data><ds:X509Certificate>MIIDsigning</ds:X509Certificate></ds:X509Data>
+        </ds:KeyInfo>
+      </KeyDescriptor>
+      <KeyDescriptor use="encryption">
+        <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+          <ds:X509Data><ds:X509Certificate>MIIDencryption</ds:X509Certificate></ds:X509Data>
+        </ds:KeyInfo>
+      </KeyDescriptor>
+      <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.onelogin.com/trust/saml2/http-redirect/slo/1"/>
+      <NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</NameIDFormat>
+      <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.onelogin.com/trust/saml2/http-redirect/sso/abc"/>
+    </IDPSSODescriptor>
+  </EntityDescriptor>
+</EntitiesDescriptor>`
+
+func TestParseSPMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.xml")
+	require.NoError(t, os.WriteFile(path, []byte(testSPMetadata), 0o600))
+
+	metadata, err := ParseSPMetadataFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "https://sp.example.com/saml", metadata.EntityID)
+	require.Equal(t, []string{"MIICAAAA"}, metadata.Certificates)
+	require.Equal(t, "https://sp.example.com/acs", metadata.AssertionConsumerService().Location)
+	require.Equal(t, "https://sp.example.com/slo", metadata.SingleLogoutService().Location)
+
+	app, err := metadata.NewApp(110016, "sp")
+	require.NoError(t, err)
+	require.Equal(t, 110016, app.ConnectorID)
+	require.Equal(t, "sp", app.Name)
+	require.Equal(t, "https://sp.example.com/saml", app.Configuration.Audience)
+	require.Equal(t, "https://sp.example.com/acs", app.Configuration.ConsumerURL)
+	require.Equal(t, "https://sp.example.com/acs", app.Configuration.Recipient)
+	require.Equal(t, "https://sp.example.com/slo", app.Configuration.LogoutURL)
+	require.Equal(t, "2", app.Configuration.SAMLNameIDFormatID)
+
+	_, err = ParseSPMetadata([]byte(testIdPMetadata))
+	require.Error(t, err)
+}
+
+func TestParseIdPMetadata(t *testing.T) {
+	metadata, err := ParseIdPMetadata([]byte(testIdPMetadata))
+	require.NoError(t, err)
+	require.Equal(t, "https://app.onelogin.com/saml/metadata/abc", metadata.EntityID)
+	require.Equal(t, []string{"MIIDsigning"}, metadata.SigningCertificates)
+	require.Equal(t, []string{NameIDFormatEmail}, metadata.NameIDFormats)
+	require.Len(t, metadata.SingleSignOnServices, 1)
+	require.Len(t, metadata.SingleLogoutServices, 1)
+}
+
+func TestFetchSPMetadata_too_large(t *testing.T) {
+	client := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat(" ", maxMetadataSize+1)))
+	})
+
+	_, err := client.FetchSPMetadata("https://sp.example.com/metadata")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "larger than")
+}
@@ -0,0 +1,46 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_GenerateSAMLAssertion_missing_fields() {
+	_, err := s.client.GenerateSAMLAssertion(&SAMLAssertionRequest{})
+	s.Equal(ErrMissingField{"username_or_email"}, err)
+
+	_, err = s.client.GenerateSAMLAssertion(&SAMLAssertionRequest{UsernameOrEmail: "user"})
+	s.Equal(ErrMissingField{"password"}, err)
+
+	_, err = s.client.GenerateSAMLAssertion(&SAMLAssertionRequest{UsernameOrEmail: "user", Password: "pass"})
+	s.Equal(ErrMissingField{"app_id"}, err)
+
+	_, err = s.client.VerifySAMLFactor(&VerifySAMLFactorRequest{AppID: 1, DeviceID: 2})
+	s.Equal(ErrMissingField{"state_token"}, err)
+}
+
+func TestSAMLAssertionRequest_JSON(t *testing.T) {
+	body, err := json.Marshal(&VerifySAMLFactorRequest{AppID: 12, DeviceID: 34, StateToken: "token"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"app_id": "12", "device_id": "34", "state_token": "token"}`, string(body))
+}
+
+func TestSAMLAssertionResponse_MFARequired(t *testing.T) {
+	var resp SAMLAssertionResponse
+	err := json.Unmarshal([]byte(`{
+		"state_token": "abc",
+		"message": "MFA is required for this user",
+		"devices": [{"device_id": 666666, "device_type": "Google Authenticator"}],
+		"callback_url": "https://api.us.onelogin.com/api/2/saml_assertion/verify_factor",
+		"user": {"id": 1, "username": "user", "email": "user@example.com"}
+	}`), &resp)
+	require.NoError(t, err)
+	require.True(t, resp.MFARequired())
+	require.Equal(t, 666666, resp.Devices[0].DeviceID)
+	require.Equal(t, "user", resp.User.Username)
+
+	resp = SAMLAssertionResponse{Assertion: "PHNhbWw+"}
+	require.False(t, resp.MFARequired())
+}
@@ -0,0 +1,126 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SelfRegistrationProfile is a sign up page new users can register
+// themselves through
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/overview
+type SelfRegistrationProfile struct {
+	ID                    int                      `json:"id,omitempty"`
+	Name                  string                   `json:"name,omitempty"`
+	URL                   string                   `json:"url,omitempty"`
+	Enabled               *bool                    `json:"enabled,omitempty"`
+	Moderated             *bool                    `json:"moderated,omitempty"`
+	HelpText              string                   `json:"helptext,omitempty"`
+	ThankYouMessage       string                   `json:"thankyou_message,omitempty"`
+	DefaultRoleID         int                      `json:"default_role_id,omitempty"`
+	DefaultGroupID        int                      `json:"default_group_id,omitempty"`
+	DomainWhitelist       string                   `json:"domain_whitelist,omitempty"`
+	DomainBlacklist       string                   `json:"domain_blacklist,omitempty"`
+	DomainListStrategy    int                      `json:"domain_list_strategy,omitempty"`
+	EmailVerificationType string                   `json:"email_verification_type,omitempty"`
+	Fields                []*SelfRegistrationField `json:"fields,omitempty"`
+}
+
+// SelfRegistrationField is a custom attribute collected on sign up
+type SelfRegistrationField struct {
+	ID                int    `json:"id,omitempty"`
+	Name              string `json:"name,omitempty"`
+	CustomAttributeID int    `json:"custom_attribute_id,omitempty"`
+}
+
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/list-profiles
+func (c *Client) ListSelfRegistrationProfiles(paging *Paging) ([]*SelfRegistrationProfile, error) {
+	var profiles []*SelfRegistrationProfile
+	err := c.execRequest(&oneloginRequest{
+		method:      GET,
+		path:        "/api/2/self_registration_profiles",
+		queryParams: addPagingParams(map[string]string{}, paging),
+		respModel:   &profiles,
+	})
+	return profiles, err
+}
+
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/get-profile
+func (c *Client) GetSelfRegistrationProfile(id int) (*SelfRegistrationProfile, error) {
+	var profile SelfRegistrationProfile
+	err := c.exec(GET, fmt.Sprintf("/api/2/self_registration_profiles/%v", id), nil, &profile)
+	return &profile, err
+}
+
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/create-profile
+func (c *Client) CreateSelfRegistrationProfile(profile *SelfRegistrationProfile) (*SelfRegistrationProfile, error) {
+	if profile.Name == "" {
+		return nil, ErrMissingField{"name"}
+	}
+	if profile.URL == "" {
+		return nil, ErrMissingField{"url"}
+	}
+
+	body, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var newProfile SelfRegistrationProfile
+	err = c.exec(POST, "/api/2/self_registration_profiles", bytes.NewReader(body), &newProfile)
+	if err != nil {
+		return nil, err
+	}
+	return &newProfile, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/update-profile
+func (c *Client) UpdateSelfRegistrationProfile(profile *SelfRegistrationProfile) (*SelfRegistrationProfile, error) {
+	if profile.ID == 0 {
+		return nil, ErrMissingField{"id"}
+	}
+
+	body, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedProfile SelfRegistrationProfile
+	err = c.exec(PUT, fmt.Sprintf("/api/2/self_registration_profiles/%v", profile.ID), bytes.NewReader(body), &updatedProfile)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedProfile, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/delete-profile
+func (c *Client) DeleteSelfRegistrationProfile(id int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/self_registration_profiles/%v", id), nil, nil)
+}
+
+// AddSelfRegistrationField adds a custom attribute to a profile's sign
+// up form
+//
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/add-field
+func (c *Client) AddSelfRegistrationField(profileID, customAttributeID int) (*SelfRegistrationField, error) {
+	if customAttributeID == 0 {
+		return nil, ErrMissingField{"custom_attribute_id"}
+	}
+
+	body, err := json.Marshal(&SelfRegistrationField{CustomAttributeID: customAttributeID})
+	if err != nil {
+		return nil, err
+	}
+
+	var field SelfRegistrationField
+	err = c.exec(POST, fmt.Sprintf("/api/2/self_registration_profiles/%v/fields", profileID), bytes.NewReader(body), &field)
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// https://developers.onelogin.com/api-docs/2/self-registration-profiles/delete-field
+func (c *Client) DeleteSelfRegistrationField(profileID, fieldID int) error {
+	return c.exec(DELETE, fmt.Sprintf("/api/2/self_registration_profiles/%v/fields/%v", profileID, fieldID), nil, nil)
+}
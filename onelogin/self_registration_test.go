@@ -0,0 +1,37 @@
+package onelogin
+
+func (s *OneLoginTestSuite) Test_SelfRegistrationProfileOperations() {
+	enabled := false
+	profile, err := s.client.CreateSelfRegistrationProfile(&SelfRegistrationProfile{
+		Name:    "test-profile",
+		URL:     "test-profile",
+		Enabled: &enabled,
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(profile.ID)
+
+	profile.HelpText = "updated by tests"
+	profile, err = s.client.UpdateSelfRegistrationProfile(profile)
+	s.Require().NoError(err)
+
+	gotProfile, err := s.client.GetSelfRegistrationProfile(profile.ID)
+	s.Require().NoError(err)
+	s.Equal("updated by tests", gotProfile.HelpText)
+
+	err = s.client.DeleteSelfRegistrationProfile(profile.ID)
+	s.NoError(err)
+}
+
+func (s *OneLoginTestSuite) Test_SelfRegistrationProfile_missing_fields() {
+	_, err := s.client.CreateSelfRegistrationProfile(&SelfRegistrationProfile{})
+	s.Equal(ErrMissingField{"name"}, err)
+
+	_, err = s.client.CreateSelfRegistrationProfile(&SelfRegistrationProfile{Name: "test"})
+	s.Equal(ErrMissingField{"url"}, err)
+
+	_, err = s.client.UpdateSelfRegistrationProfile(&SelfRegistrationProfile{})
+	s.Equal(ErrMissingField{"id"}, err)
+
+	_, err = s.client.AddSelfRegistrationField(1, 0)
+	s.Equal(ErrMissingField{"custom_attribute_id"}, err)
+}
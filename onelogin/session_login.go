@@ -0,0 +1,186 @@
+package onelogin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultFactorPollInterval = 2 * time.Second
+
+	// DefaultFactorPollTimeout bounds PollSessionFactor when its context
+	// has no deadline
+	DefaultFactorPollTimeout = 5 * time.Minute
+)
+
+type SessionLoginRequest struct {
+	UsernameOrEmail string `json:"username_or_email"`
+	Password        string `json:"password"`
+	// Subdomain defaults to the subdomain the client is configured with
+	Subdomain string `json:"subdomain"`
+}
+
+type VerifySessionFactorRequest struct {
+	DeviceID    int    `json:"device_id,string"`
+	StateToken  string `json:"state_token"`
+	OTPToken    string `json:"otp_token,omitempty"`
+	DoNotNotify bool   `json:"do_not_notify,omitempty"`
+}
+
+// SessionLogin is the state of a session login after each step.  Once
+// authenticated SessionToken is set.  When MFA is required StateToken
+// and Devices are set and the flow continues with VerifySessionFactor.
+// Pending is set while a push notification awaits the user's approval.
+type SessionLogin struct {
+	Status       string       `json:"status,omitempty"`
+	SessionToken string       `json:"session_token,omitempty"`
+	ExpiresAt    string       `json:"expires_at,omitempty"`
+	ReturnToURL  string       `json:"return_to_url,omitempty"`
+	User         *FactorUser  `json:"user,omitempty"`
+	StateToken   string       `json:"state_token,omitempty"`
+	Devices      []*MFADevice `json:"devices,omitempty"`
+	CallbackURL  string       `json:"callback_url,omitempty"`
+	Pending      bool         `json:"-"`
+}
+
+// MFARequired reports whether a factor must be verified to finish the login
+func (l *SessionLogin) MFARequired() bool {
+	return l.SessionToken == "" && l.StateToken != ""
+}
+
+// StartSessionLogin begins a session login with a username and password
+//
+// https://developers.onelogin.com/api-docs/1/users/create-session-login-token
+func (c *Client) StartSessionLogin(request *SessionLoginRequest) (*SessionLogin, error) {
+	if request.UsernameOrEmail == "" {
+		return nil, ErrMissingField{"username_or_email"}
+	}
+	if request.Password == "" {
+		return nil, ErrMissingField{"password"}
+	}
+
+	r := *request
+	if r.Subdomain == "" {
+		r.Subdomain = c.config.Subdomain
+	}
+
+	return c.sessionLogin("/api/1/login/auth", &r)
+}
+
+// VerifySessionFactor verifies an OTP, or sends a push notification to
+// the device when OTPToken is empty.  A pending push is returned with
+// Pending set, use PollSessionFactor to wait for the user to respond.
+//
+// https://developers.onelogin.com/api-docs/1/users/verify-factor
+func (c *Client) VerifySessionFactor(request *VerifySessionFactorRequest) (*SessionLogin, error) {
+	if request.DeviceID == 0 {
+		return nil, ErrMissingField{"device_id"}
+	}
+	if request.StateToken == "" {
+		return nil, ErrMissingField{"state_token"}
+	}
+
+	return c.sessionLogin("/api/1/login/verify_factor", request)
+}
+
+// PollSessionFactor calls VerifySessionFactor until the login is no
+// longer pending.  The push notification is only sent by the first
+// call.  ErrMFATimeout is returned if ctx's deadline passes first, and
+// ctx's error if it is canceled.  A ctx without a deadline is given
+// DefaultFactorPollTimeout.
+func (c *Client) PollSessionFactor(ctx context.Context, request *VerifySessionFactorRequest, interval time.Duration) (*SessionLogin, error) {
+	if interval == 0 {
+		interval = DefaultFactorPollInterval
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultFactorPollTimeout)
+		defer cancel()
+	}
+
+	client := c.WithContext(ctx)
+	r := *request
+	for {
+		login, err := client.VerifySessionFactor(&r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, pollError(ctx)
+			}
+			return nil, err
+		}
+		if !login.Pending {
+			return login, nil
+		}
+		r.DoNotNotify = true
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, pollError(ctx)
+		case <-timer.C:
+		}
+	}
+}
+
+func pollError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrMFATimeout{}
+	}
+	return ctx.Err()
+}
+
+func (c *Client) sessionLogin(path string, request interface{}) (*SessionLogin, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var logins []*SessionLogin
+	resp := v1Response{Data: &logins}
+	err = c.execRequest(&oneloginRequest{
+		method:    POST,
+		path:      path,
+		body:      bytes.NewReader(body),
+		respModel: &resp,
+		readOnly:  true,
+	})
+	if err != nil {
+		return nil, sessionLoginError(err)
+	}
+
+	if strings.EqualFold(resp.Status.Type, "pending") {
+		return &SessionLogin{Pending: true}, nil
+	}
+	if len(logins) == 0 {
+		return nil, ErrInvalidResponse{Reason: "login response has no data"}
+	}
+	return logins[0], nil
+}
+
+// sessionLoginError converts the status of a failed login into a typed error
+func sessionLoginError(err error) error {
+	var reqErr ErrRequestFailed
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+
+	var resp v1Response
+	if json.Unmarshal(reqErr.Body, &resp) != nil {
+		return err
+	}
+
+	message := resp.Status.Message
+	switch {
+	case reqErr.StatusCode == http.StatusLocked || resp.Status.Code == http.StatusLocked:
+		return ErrUserLocked{Message: message}
+	case reqErr.StatusCode == http.StatusUnauthorized:
+		return ErrInvalidCredentials{Message: message}
+	}
+	return err
+}
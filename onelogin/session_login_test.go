@@ -0,0 +1,104 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_StartSessionLogin_invalid_credentials() {
+	_, err := s.client.StartSessionLogin(&SessionLoginRequest{
+		UsernameOrEmail: "test_username_does_not_exist",
+		Password:        "not-the-password",
+	})
+	s.Require().Error(err)
+	s.IsType(ErrInvalidCredentials{}, err)
+}
+
+func (s *OneLoginTestSuite) Test_SessionLogin_missing_fields() {
+	_, err := s.client.StartSessionLogin(&SessionLoginRequest{})
+	s.Equal(ErrMissingField{"username_or_email"}, err)
+
+	_, err = s.client.StartSessionLogin(&SessionLoginRequest{UsernameOrEmail: "user"})
+	s.Equal(ErrMissingField{"password"}, err)
+
+	_, err = s.client.VerifySessionFactor(&VerifySessionFactorRequest{DeviceID: 1})
+	s.Equal(ErrMissingField{"state_token"}, err)
+}
+
+func TestSessionLoginError(t *testing.T) {
+	err := sessionLoginError(ErrRequestFailed{
+		StatusCode: 401,
+		Body:       []byte(`{"status": {"error": true, "code": 401, "type": "Unauthorized", "message": "Authentication Failed: Invalid user credentials"}}`),
+	})
+	require.Equal(t, ErrInvalidCredentials{Message: "Authentication Failed: Invalid user credentials"}, err)
+
+	err = sessionLoginError(ErrRequestFailed{
+		StatusCode: 423,
+		Body:       []byte(`{"status": {"error": true, "code": 423, "type": "Locked", "message": "Authentication Failed: User is locked"}}`),
+	})
+	require.Equal(t, ErrUserLocked{Message: "Authentication Failed: User is locked"}, err)
+
+	// the message alone does not make a lock
+	err = sessionLoginError(ErrRequestFailed{
+		StatusCode: 401,
+		Body:       []byte(`{"status": {"error": true, "code": 401, "type": "Unauthorized", "message": "Authentication Failed: unlocked device"}}`),
+	})
+	require.IsType(t, ErrInvalidCredentials{}, err)
+
+	other := errors.New("other")
+	require.Equal(t, other, sessionLoginError(other))
+
+	server := ErrRequestFailed{StatusCode: 500, Body: []byte("oops")}
+	require.Equal(t, server, sessionLoginError(server))
+}
+
+func TestPollSessionFactor(t *testing.T) {
+	type key struct{}
+	var cancel context.CancelFunc
+	var polls int
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		// the requests are made with the poll's context
+		require.Equal(t, "poll", r.Context().Value(key{}))
+		polls++
+		if polls == 2 && cancel != nil {
+			cancel()
+		}
+		w.Write([]byte(`{"status": {"error": false, "code": 200, "type": "pending", "message": "Authentication pending"}}`))
+	})
+	request := &VerifySessionFactorRequest{DeviceID: 1, StateToken: "state"}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "poll"))
+	_, err := c.PollSessionFactor(ctx, request, time.Millisecond)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 2, polls)
+
+	ctx, timeout := context.WithTimeout(context.WithValue(context.Background(), key{}, "poll"), 20*time.Millisecond)
+	defer timeout()
+	cancel = nil
+	_, err = c.PollSessionFactor(ctx, request, time.Millisecond)
+	require.Equal(t, ErrMFATimeout{}, err)
+}
+
+func TestPollSessionFactor_default_timeout(t *testing.T) {
+	c := newTestClient(ClientConfig{Timeout: time.Hour}, func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		require.True(t, ok)
+		require.WithinDuration(t, time.Now().Add(DefaultFactorPollTimeout), deadline, time.Minute)
+		w.Write([]byte(`{"status": {"error": false, "code": 200, "type": "success", "message": "Success"}, "data": [{"status": "Authenticated"}]}`))
+	})
+	_, err := c.PollSessionFactor(context.Background(), &VerifySessionFactorRequest{DeviceID: 1, StateToken: "state"}, time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestSessionLogin_no_data(t *testing.T) {
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": {"error": false, "code": 200, "type": "success", "message": "Success"}, "data": []}`))
+	})
+	_, err := c.VerifySessionFactor(&VerifySessionFactorRequest{DeviceID: 1, StateToken: "state"})
+	require.IsType(t, ErrInvalidResponse{}, err)
+}
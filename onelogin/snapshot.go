@@ -0,0 +1,591 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// WriteSnapshot.  Snapshots with a newer version are rejected.
+const SnapshotVersion = 1
+
+// Snapshot is a copy of a tenant's configuration.  IDs are those of the
+// tenant the snapshot was exported from, RestoreSnapshot remaps them.
+type Snapshot struct {
+	Version          int                `json:"version"`
+	CreatedAt        time.Time          `json:"created_at"`
+	Subdomain        string             `json:"subdomain"`
+	CustomAttributes []*CustomAttribute `json:"custom_attributes"`
+	Users            []*User            `json:"users"`
+	Apps             []*SnapshotApp     `json:"apps"`
+	Roles            []*Role            `json:"roles"`
+	Mappings         []*Mapping         `json:"mappings"`
+}
+
+// SnapshotApp is an app with its parameters, configuration and rules
+type SnapshotApp struct {
+	*App
+	Rules []*AppRule `json:"rules"`
+}
+
+// RestoredResource is a resource re-created by RestoreSnapshot
+type RestoredResource struct {
+	Kind  ResourceKind
+	Name  string
+	OldID int
+	NewID int
+}
+
+// RestoreFailure is a resource RestoreSnapshot failed to re-create or
+// to finish setting up
+type RestoreFailure struct {
+	Kind  ResourceKind
+	Name  string
+	OldID int
+	Err   error
+}
+
+// RestoreResult lists what a restore created and what failed.  IDs maps
+// the id of every resource in the snapshot to its id in the restored
+// tenant, including resources that already existed.
+type RestoreResult struct {
+	Created []*RestoredResource
+	Failed  []*RestoreFailure
+	IDs     map[ResourceKind]map[int]int
+}
+
+func (r *RestoreResult) mapID(kind ResourceKind, oldID, newID int) {
+	if r.IDs[kind] == nil {
+		r.IDs[kind] = map[int]int{}
+	}
+	r.IDs[kind][oldID] = newID
+}
+
+func (r *RestoreResult) created(kind ResourceKind, name string, oldID, newID int) {
+	r.Created = append(r.Created, &RestoredResource{Kind: kind, Name: name, OldID: oldID, NewID: newID})
+	r.mapID(kind, oldID, newID)
+}
+
+func (r *RestoreResult) failed(kind ResourceKind, name string, oldID int, err error) {
+	r.Failed = append(r.Failed, &RestoreFailure{Kind: kind, Name: name, OldID: oldID, Err: err})
+}
+
+// err joins the failures and err, which stopped the restore
+func (r *RestoreResult) err(err error) error {
+	errs := make([]error, 0, len(r.Failed)+1)
+	for _, failure := range r.Failed {
+		errs = append(errs, fmt.Errorf("%s %q: %w", failure.Kind, failure.Name, failure.Err))
+	}
+	return errors.Join(append(errs, err)...)
+}
+
+// remap converts snapshot ids, dropping those that were not restored
+func (r *RestoreResult) remap(kind ResourceKind, ids []int) []int {
+	var remapped []int
+	for _, id := range ids {
+		if newID, ok := r.IDs[kind][id]; ok {
+			remapped = append(remapped, newID)
+		}
+	}
+	return remapped
+}
+
+func (r *RestoreResult) wasCreated(kind ResourceKind, oldID int) bool {
+	for _, created := range r.Created {
+		if created.Kind == kind && created.OldID == oldID {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportSnapshot reads users, roles with their members and admins, apps
+// with their parameters, configuration and rules, mappings and custom
+// attribute definitions.  App client secrets are left out since the
+// snapshot is written to disk and restores do not set them.
+func (c *Client) ExportSnapshot() (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Subdomain: c.config.Subdomain,
+	}
+
+	var err error
+	snapshot.CustomAttributes, err = c.ListCustomAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Users, err = listAll(func(paging Paging) ([]*User, error) {
+		return c.ListUsers(&UserQuery{Paging: paging})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := listAll(func(paging Paging) ([]*AppQueryResponse, error) {
+		return c.ListApps(&AppQuery{Paging: paging})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range apps {
+		app, err := c.GetApp(summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		if app.SSO != nil {
+			app.SSO.ClientSecret = ""
+		}
+		rules, err := c.ListAppRules(summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Apps = append(snapshot.Apps, &SnapshotApp{App: app, Rules: rules})
+	}
+
+	roles, err := listAll(func(paging Paging) ([]*Role, error) {
+		return c.ListRoles(&RoleQuery{Paging: paging})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range roles {
+		role, err := c.GetRole(summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Roles = append(snapshot.Roles, role)
+	}
+
+	for _, enabled := range []bool{true, false} {
+		mappings, err := c.ListMappings(enabled)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Mappings = append(snapshot.Mappings, mappings...)
+	}
+
+	return snapshot, nil
+}
+
+// WriteSnapshot encodes a snapshot as indented JSON
+func WriteSnapshot(w io.Writer, snapshot *Snapshot) error {
+	if snapshot.Version == 0 {
+		snapshot.Version = SnapshotVersion
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// ReadSnapshot decodes a snapshot written by WriteSnapshot
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version < 1 || snapshot.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %v", snapshot.Version)
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot writes a snapshot to a file, replacing it atomically
+func SaveSnapshot(path string, snapshot *Snapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteSnapshot(tmp, snapshot); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshot reads a snapshot file
+func LoadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSnapshot(f)
+}
+
+// RestoreSnapshot re-creates the resources of a snapshot that are
+// missing from the tenant.  Custom attributes are matched by shortname,
+// users by username and the others by name, existing resources are left
+// unchanged.  Resources are created in dependency order and the ids they
+// reference are remapped to the restored tenant.
+//
+// Passwords and client secrets are not exported, restored users must
+// reset their password and restored OIDC apps get a new client secret.
+// A resource that fails is recorded in the result's Failed and the
+// restore continues, references to it are dropped.  The restore only
+// stops when the tenant's existing resources cannot be listed.  The
+// returned error joins every failure.
+func (c *Client) RestoreSnapshot(snapshot *Snapshot) (*RestoreResult, error) {
+	result := &RestoreResult{IDs: map[ResourceKind]map[int]int{}}
+
+	steps := []func(*Snapshot, *RestoreResult) error{
+		c.restoreCustomAttributes,
+		c.restoreUsers,
+		c.restoreApps,
+		c.restoreRoles,
+		c.restoreAppRules,
+		c.restoreMappings,
+	}
+	for _, step := range steps {
+		if err := step(snapshot, result); err != nil {
+			return result, result.err(err)
+		}
+	}
+	return result, result.err(nil)
+}
+
+func (c *Client) restoreCustomAttributes(snapshot *Snapshot, result *RestoreResult) error {
+	live, err := c.ListCustomAttributes()
+	if err != nil {
+		return err
+	}
+	existing := map[string]int{}
+	for _, attribute := range live {
+		existing[attribute.Shortname] = attribute.ID
+	}
+
+	for _, attribute := range snapshot.CustomAttributes {
+		if id, ok := existing[attribute.Shortname]; ok {
+			result.mapID(ResourceCustomAttribute, attribute.ID, id)
+			continue
+		}
+		created, err := c.CreateCustomAttribute(&CustomAttribute{Name: attribute.Name, Shortname: attribute.Shortname})
+		if err != nil {
+			result.failed(ResourceCustomAttribute, attribute.Shortname, attribute.ID, err)
+			continue
+		}
+		result.created(ResourceCustomAttribute, attribute.Shortname, attribute.ID, created.ID)
+	}
+	return nil
+}
+
+func (c *Client) restoreUsers(snapshot *Snapshot, result *RestoreResult) error {
+	live, err := listAll(func(paging Paging) ([]*User, error) {
+		return c.ListUsers(&UserQuery{Paging: paging})
+	})
+	if err != nil {
+		return err
+	}
+	existing := map[string]int{}
+	for _, user := range live {
+		existing[user.UserName] = user.ID
+	}
+
+	for _, user := range snapshot.Users {
+		if id, ok := existing[user.UserName]; ok {
+			result.mapID(ResourceUser, user.ID, id)
+			continue
+		}
+
+		created, err := c.CreateUser(restorableUser(user))
+		if err != nil {
+			result.failed(ResourceUser, user.UserName, user.ID, err)
+			continue
+		}
+		result.created(ResourceUser, user.UserName, user.ID, created.ID)
+	}
+
+	// managers are linked once every user exists, since a manager can
+	// come after their reports in the snapshot
+	for _, user := range snapshot.Users {
+		if user.ManagerUserID == 0 || !result.wasCreated(ResourceUser, user.ID) {
+			continue
+		}
+		managerID, ok := result.IDs[ResourceUser][user.ManagerUserID]
+		if !ok {
+			continue
+		}
+		_, err := c.UpdateUser(&User{ID: result.IDs[ResourceUser][user.ID], ManagerUserID: managerID})
+		if err != nil {
+			result.failed(ResourceUser, user.UserName, user.ID, fmt.Errorf("manager: %w", err))
+		}
+	}
+	return nil
+}
+
+// restorableUser copies the fields of a user that can be set on create
+func restorableUser(user *User) *User {
+	restored := *user
+	restored.ID = 0
+	restored.RoleIDs = nil
+	restored.ManagerUserID = 0
+	restored.InvalidLoginAttempts = 0
+	restored.CreatedAt = nil
+	restored.UpdatedAt = nil
+	restored.ActivatedAt = nil
+	restored.LastLogin = nil
+	restored.PasswordChangedAt = nil
+	restored.LockedUntil = nil
+	restored.InvitationSentAt = nil
+	return &restored
+}
+
+func (c *Client) restoreApps(snapshot *Snapshot, result *RestoreResult) error {
+	live, err := listAll(func(paging Paging) ([]*AppQueryResponse, error) {
+		return c.ListApps(&AppQuery{Paging: paging})
+	})
+	if err != nil {
+		return err
+	}
+	existing := map[string]int{}
+	for _, app := range live {
+		existing[app.Name] = app.ID
+	}
+
+	for _, app := range snapshot.Apps {
+		if id, ok := existing[app.Name]; ok {
+			result.mapID(ResourceApp, app.ID, id)
+			continue
+		}
+
+		created, err := c.CreateApp(restorableApp(app.App))
+		if err != nil {
+			result.failed(ResourceApp, app.Name, app.ID, err)
+			continue
+		}
+		result.created(ResourceApp, app.Name, app.ID, created.ID)
+	}
+	return nil
+}
+
+// restorableApp copies the fields of an app that can be set on create
+func restorableApp(app *App) *App {
+	restored := *app
+	restored.ID = 0
+	restored.RoleIDs = nil
+	restored.SSO = nil
+	restored.CreatedAt = nil
+	restored.UpdatedAt = nil
+	if app.Parameters != nil {
+		restored.Parameters = map[string]*Parameter{}
+		for name, parameter := range app.Parameters {
+			p := *parameter
+			p.ID = 0
+			restored.Parameters[name] = &p
+		}
+	}
+	return &restored
+}
+
+func (c *Client) restoreRoles(snapshot *Snapshot, result *RestoreResult) error {
+	live, err := listAll(func(paging Paging) ([]*Role, error) {
+		return c.ListRoles(&RoleQuery{Paging: paging})
+	})
+	if err != nil {
+		return err
+	}
+	existing := map[string]int{}
+	for _, role := range live {
+		existing[role.Name] = role.ID
+	}
+
+	for _, role := range snapshot.Roles {
+		if id, ok := existing[role.Name]; ok {
+			result.mapID(ResourceRole, role.ID, id)
+			continue
+		}
+
+		created, err := c.CreateRole(&Role{Name: role.Name})
+		if err != nil {
+			result.failed(ResourceRole, role.Name, role.ID, err)
+			continue
+		}
+		result.created(ResourceRole, role.Name, role.ID, created.ID)
+
+		_, err = c.UpdateRole(&Role{
+			ID:     created.ID,
+			Name:   role.Name,
+			Apps:   result.remap(ResourceApp, role.Apps),
+			Users:  result.remap(ResourceUser, role.Users),
+			Admins: result.remap(ResourceUser, role.Admins),
+		})
+		if err != nil {
+			result.failed(ResourceRole, role.Name, role.ID, fmt.Errorf("members: %w", err))
+		}
+	}
+	return nil
+}
+
+// restoreAppRules adds the rules of the apps that were re-created.  Rules
+// can assign roles so they are restored once the roles exist.
+func (c *Client) restoreAppRules(snapshot *Snapshot, result *RestoreResult) error {
+	for _, app := range snapshot.Apps {
+		if !result.wasCreated(ResourceApp, app.ID) {
+			continue
+		}
+		appID := result.IDs[ResourceApp][app.ID]
+		for _, rule := range app.Rules {
+			restored := *rule
+			restored.ID = 0
+			restored.Conditions, restored.Actions = remapRule(rule.Conditions, rule.Actions, result)
+			created, err := c.CreateAppRule(appID, &restored)
+			if err != nil {
+				result.failed(ResourceAppRule, rule.Name, rule.ID, fmt.Errorf("app %q: %w", app.Name, err))
+				continue
+			}
+			result.created(ResourceAppRule, rule.Name, rule.ID, created.ID)
+		}
+	}
+	return nil
+}
+
+// restoreMappings creates the missing mappings without a position, so
+// they are added after the tenant's mappings, then moves each enabled
+// one after the mapping that preceded it in the snapshot
+func (c *Client) restoreMappings(snapshot *Snapshot, result *RestoreResult) error {
+	existing := map[string]int{}
+	var order []int
+	for _, enabled := range []bool{true, false} {
+		live, err := c.ListMappings(enabled)
+		if err != nil {
+			return err
+		}
+		for _, mapping := range live {
+			existing[mapping.Name] = mapping.ID
+		}
+		if enabled {
+			order = mappingOrder(live)
+		}
+	}
+
+	for _, mapping := range snapshot.Mappings {
+		if id, ok := existing[mapping.Name]; ok {
+			result.mapID(ResourceMapping, mapping.ID, id)
+			continue
+		}
+
+		restored := *mapping
+		restored.ID = 0
+		restored.Position = nil
+		restored.Conditions, restored.Actions = remapRule(mapping.Conditions, mapping.Actions, result)
+		created, err := c.CreateMapping(&restored)
+		if err != nil {
+			result.failed(ResourceMapping, mapping.Name, mapping.ID, err)
+			continue
+		}
+		result.created(ResourceMapping, mapping.Name, mapping.ID, created.ID)
+	}
+
+	var enabled []*Mapping
+	for _, mapping := range snapshot.Mappings {
+		if mapping.Enabled {
+			enabled = append(enabled, mapping)
+		}
+	}
+	sorted, moved := restoredMappingOrder(order, mappingOrder(enabled), result)
+	if !moved {
+		return nil
+	}
+	if _, err := c.SortMappings(sorted); err != nil {
+		return fmt.Errorf("sorting mappings: %w", err)
+	}
+	return nil
+}
+
+// mappingOrder returns the ids of mappings by position, mappings without
+// a position last
+func mappingOrder(mappings []*Mapping) []int {
+	sorted := slices.Clone(mappings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[j].Position == nil {
+			return sorted[i].Position != nil
+		}
+		return sorted[i].Position != nil && *sorted[i].Position < *sorted[j].Position
+	})
+	ids := make([]int, len(sorted))
+	for i, mapping := range sorted {
+		ids[i] = mapping.ID
+	}
+	return ids
+}
+
+// restoredMappingOrder inserts the ids of the mappings created from
+// snapshotOrder into the live order, each after the new id of the closest
+// preceding snapshot mapping that is in the list, or first when there
+// is none.  It reports whether any mapping was inserted.
+func restoredMappingOrder(live, snapshotOrder []int, result *RestoreResult) ([]int, bool) {
+	order := slices.Clone(live)
+	moved := false
+	for i, oldID := range snapshotOrder {
+		if !result.wasCreated(ResourceMapping, oldID) {
+			continue
+		}
+		newID := result.IDs[ResourceMapping][oldID]
+
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			previous, ok := result.IDs[ResourceMapping][snapshotOrder[j]]
+			if !ok {
+				continue
+			}
+			if k := slices.Index(order, previous); k >= 0 {
+				at = k + 1
+				break
+			}
+		}
+		order = slices.Insert(order, at, newID)
+		moved = true
+	}
+	return order, moved
+}
+
+// remapRule copies the conditions and actions of a mapping or app rule,
+// converting the role ids they reference
+func remapRule(conditions []*MappingCondition, actions []*MappingAction, result *RestoreResult) ([]*MappingCondition, []*MappingAction) {
+	remapRole := func(value string) string {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return value
+		}
+		if newID, ok := result.IDs[ResourceRole][id]; ok {
+			return strconv.Itoa(newID)
+		}
+		return value
+	}
+
+	remappedConditions := make([]*MappingCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		remapped := *condition
+		if condition.Source == "has_role" {
+			remapped.Value = remapRole(condition.Value)
+		}
+		remappedConditions = append(remappedConditions, &remapped)
+	}
+
+	remappedActions := make([]*MappingAction, 0, len(actions))
+	for _, action := range actions {
+		remapped := *action
+		if action.Action == "add_role" || action.Action == "set_role" {
+			remapped.Value = make([]string, 0, len(action.Value))
+			for _, value := range action.Value {
+				remapped.Value = append(remapped.Value, remapRole(value))
+			}
+		}
+		remappedActions = append(remappedActions, &remapped)
+	}
+	return remappedConditions, remappedActions
+}
@@ -0,0 +1,207 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *OneLoginTestSuite) Test_ExportSnapshot() {
+	snapshot, err := s.client.ExportSnapshot()
+	s.Require().NoError(err)
+	s.Equal(SnapshotVersion, snapshot.Version)
+	s.NotEmpty(snapshot.Users)
+
+	var buf bytes.Buffer
+	s.Require().NoError(WriteSnapshot(&buf, snapshot))
+	read, err := ReadSnapshot(&buf)
+	s.Require().NoError(err)
+	s.Equal(len(snapshot.Apps), len(read.Apps))
+}
+
+func TestSnapshot_roundtrip(t *testing.T) {
+	position := 1
+	snapshot := &Snapshot{
+		CreatedAt:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Subdomain:        "example",
+		CustomAttributes: []*CustomAttribute{{ID: 1, Name: "Cost Center", Shortname: "cost_center"}},
+		Users:            []*User{{ID: 10, UserName: "alice", Email: "alice@example.com"}},
+		Apps: []*SnapshotApp{{
+			App:   &App{ID: 20, Name: "Intranet", ConnectorID: 110016, AuthMethod: AppAuthMethodSAML},
+			Rules: []*AppRule{{ID: 30, Name: "Admins", Match: "all", Position: &position}},
+		}},
+		Roles:    []*Role{{ID: 40, Name: "Staff", Apps: []int{20}, Users: []int{10}}},
+		Mappings: []*Mapping{{ID: 50, Name: "Default", Match: "any"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, SaveSnapshot(path, snapshot))
+	read, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, read)
+	require.Equal(t, SnapshotVersion, read.Version)
+}
+
+func TestReadSnapshot_version(t *testing.T) {
+	_, err := ReadSnapshot(strings.NewReader(`{"version": 99}`))
+	require.ErrorContains(t, err, "unsupported snapshot version 99")
+
+	_, err = ReadSnapshot(strings.NewReader(`{}`))
+	require.Error(t, err)
+}
+
+func TestRestoreResult_remap(t *testing.T) {
+	result := &RestoreResult{IDs: map[ResourceKind]map[int]int{}}
+	result.mapID(ResourceRole, 1, 101)
+	result.created(ResourceRole, "Staff", 2, 102)
+
+	require.Equal(t, []int{101, 102}, result.remap(ResourceRole, []int{1, 2, 3}))
+	require.True(t, result.wasCreated(ResourceRole, 2))
+	require.False(t, result.wasCreated(ResourceRole, 1))
+
+	conditions, actions := remapRule(
+		[]*MappingCondition{
+			{Source: "has_role", Operator: "ri", Value: "1"},
+			{Source: "email", Operator: "=", Value: "1"},
+		},
+		[]*MappingAction{
+			{Action: "add_role", Value: []string{"1", "2", "3"}},
+			{Action: "set_status", Value: []string{"1"}},
+		},
+		result,
+	)
+	require.Equal(t, []*MappingCondition{
+		{Source: "has_role", Operator: "ri", Value: "101"},
+		{Source: "email", Operator: "=", Value: "1"},
+	}, conditions)
+	require.Equal(t, []*MappingAction{
+		{Action: "add_role", Value: []string{"101", "102", "3"}},
+		{Action: "set_status", Value: []string{"1"}},
+	}, actions)
+}
+
+func TestRestorableApp(t *testing.T) {
+	now := time.Now()
+	app := &App{
+		ID:         1,
+		Name:       "Intranet",
+		RoleIDs:    []int{2},
+		SSO:        &SSO{ClientID: "id"},
+		CreatedAt:  &now,
+		Parameters: map[string]*Parameter{"email": {ID: 3, Label: "Email"}},
+	}
+
+	restored := restorableApp(app)
+	require.Equal(t, &App{
+		Name:       "Intranet",
+		Parameters: map[string]*Parameter{"email": {Label: "Email"}},
+	}, restored)
+	require.Equal(t, 3, app.Parameters["email"].ID)
+}
+
+func TestExportSnapshot_redacts_client_secret(t *testing.T) {
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/2/apps":
+			w.Write([]byte(`[{"id": 1, "name": "Portal"}]`))
+		case "/api/2/apps/1":
+			w.Write([]byte(`{"id": 1, "name": "Portal", "auth_method": 8, "sso": {"client_id": "id", "client_secret": "secret"}}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+
+	snapshot, err := c.ExportSnapshot()
+	require.NoError(t, err)
+	require.Equal(t, &SSO{ClientID: "id"}, snapshot.Apps[0].SSO)
+}
+
+func TestRestoreUsers_manager_after_report(t *testing.T) {
+	nextID := 100
+	updates := map[string]string{}
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`[]`))
+		case http.MethodPost:
+			nextID++
+			json.NewEncoder(w).Encode(&User{ID: nextID})
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			updates[r.URL.Path] = string(body)
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	snapshot := &Snapshot{Users: []*User{
+		{ID: 2, UserName: "bob", Email: "bob@example.com", ManagerUserID: 1},
+		{ID: 1, UserName: "alice", Email: "alice@example.com"},
+	}}
+	result := &RestoreResult{IDs: map[ResourceKind]map[int]int{}}
+	require.NoError(t, c.restoreUsers(snapshot, result))
+
+	require.Equal(t, map[int]int{2: 101, 1: 102}, result.IDs[ResourceUser])
+	require.Equal(t, map[string]string{"/api/2/users/101": `{"id":101,"manager_user_id":102}`}, updates)
+}
+
+func TestRestoreMappings(t *testing.T) {
+	var sorted string
+	c := newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("enabled") == "true" {
+				w.Write([]byte(`[{"id": 11, "name": "Live B", "enabled": true, "position": 2}, {"id": 10, "name": "Live A", "enabled": true, "position": 1}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case http.MethodPost:
+			var mapping Mapping
+			json.NewDecoder(r.Body).Decode(&mapping)
+			require.Nil(t, mapping.Position)
+			if mapping.Name == "Failing" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				return
+			}
+			w.Write([]byte(`{"id": 20}`))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			sorted = string(body)
+			w.Write(body)
+		}
+	})
+
+	one, two, three := 1, 2, 3
+	snapshot := &Snapshot{Mappings: []*Mapping{
+		{ID: 3, Name: "Failing", Match: "all", Enabled: true, Position: &three},
+		{ID: 2, Name: "Restored", Match: "all", Enabled: true, Position: &two},
+		{ID: 1, Name: "Live A", Match: "all", Enabled: true, Position: &one},
+	}}
+	result := &RestoreResult{IDs: map[ResourceKind]map[int]int{}}
+	require.NoError(t, c.restoreMappings(snapshot, result))
+
+	require.Equal(t, "[10,20,11]", sorted)
+	require.Len(t, result.Failed, 1)
+	require.Equal(t, "Failing", result.Failed[0].Name)
+	require.ErrorContains(t, result.err(nil), `mapping "Failing"`)
+}
+
+func TestRestoredMappingOrder(t *testing.T) {
+	result := &RestoreResult{IDs: map[ResourceKind]map[int]int{}}
+	result.created(ResourceMapping, "First", 1, 101)
+	result.mapID(ResourceMapping, 2, 12)
+	result.created(ResourceMapping, "Third", 3, 103)
+
+	order, moved := restoredMappingOrder([]int{11, 12}, []int{1, 2, 3}, result)
+	require.True(t, moved)
+	require.Equal(t, []int{101, 11, 12, 103}, order)
+
+	_, moved = restoredMappingOrder([]int{11, 12}, []int{2}, result)
+	require.False(t, moved)
+}
@@ -0,0 +1,511 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ghaggin/onelogin-go-client/internal/yamljson"
+)
+
+// DefaultImportConcurrency is the number of rows imported at once when
+// UserImportConfig.Concurrency is not set
+const DefaultImportConcurrency = 4
+
+// UserImportConfig maps the columns of a CSV file to user fields.
+// Columns are keyed by CSV header and name the user field by its API
+// name, e.g. "Work Email": "email".  Custom attributes are named
+// custom_attributes.<shortname>.  Empty cells leave the field unset.
+type UserImportConfig struct {
+	Columns map[string]string `json:"columns"`
+
+	// MatchBy is the field existing users are matched on: username,
+	// email or external_id.  Defaults to username.
+	MatchBy string `json:"match_by,omitempty"`
+
+	// CreateOnly skips rows matching an existing user instead of
+	// updating it
+	CreateOnly bool `json:"create_only,omitempty"`
+
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// ResultsPath is the CSV file the outcome of each row is written to
+	// as it completes
+	ResultsPath string `json:"results_path,omitempty"`
+
+	// Resume skips the rows ResultsPath records as created, updated or
+	// skipped by an earlier run, failed rows are retried
+	Resume bool `json:"resume,omitempty"`
+}
+
+// LoadUserImportConfig reads a YAML or JSON import config
+func LoadUserImportConfig(path string) (*UserImportConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := yamljson.Convert(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var config UserImportConfig
+	if err := json.Unmarshal(jsonData, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ImportOutcome is what happened to a row of an import
+type ImportOutcome string
+
+const (
+	ImportCreated ImportOutcome = "created"
+	ImportUpdated ImportOutcome = "updated"
+	ImportSkipped ImportOutcome = "skipped"
+	ImportFailed  ImportOutcome = "failed"
+)
+
+// UserImportResult is the outcome of a row.  Row is the 1 based row
+// number not counting the header and Key is the value matched on.
+type UserImportResult struct {
+	Row     int
+	Key     string
+	Outcome ImportOutcome
+	UserID  int
+	Error   string
+}
+
+// UserImportSummary counts the outcomes of an import.  Resumed counts
+// the rows skipped because an earlier run completed them.
+type UserImportSummary struct {
+	Created int
+	Updated int
+	Skipped int
+	Failed  int
+	Resumed int
+	Results []*UserImportResult
+}
+
+var userImportResultsHeader = []string{"row", "key", "outcome", "user_id", "error"}
+
+var importMatchFields = map[string]bool{"username": true, "email": true, "external_id": true}
+
+// ImportUsers creates or updates a user for each row of a CSV file.
+// Users that already match the row are skipped.  Passwords cannot be
+// compared, so a row setting a password always updates its user.  Rows
+// with the same key are imported one after another.  A row that fails is
+// recorded and the import continues, the returned error is reserved for
+// problems with the input, the results file or ctx being done.
+func (c *Client) ImportUsers(ctx context.Context, r io.Reader, config *UserImportConfig) (*UserImportSummary, error) {
+	matchBy := config.MatchBy
+	if matchBy == "" {
+		matchBy = "username"
+	}
+	if !importMatchFields[matchBy] {
+		return nil, fmt.Errorf("cannot match users by %q", matchBy)
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultImportConcurrency
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	mapper, err := newUserRowMapper(header, config.Columns, matchBy)
+	if err != nil {
+		return nil, err
+	}
+
+	done := map[int]bool{}
+	if config.Resume && config.ResultsPath != "" {
+		done, err = readCompletedRows(config.ResultsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results *csv.Writer
+	if config.ResultsPath != "" {
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if config.Resume {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(config.ResultsPath, flags, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		results = csv.NewWriter(f)
+		if info, err := f.Stat(); err == nil && info.Size() == 0 {
+			if err := results.Write(userImportResultsHeader); err != nil {
+				return nil, err
+			}
+			results.Flush()
+			if err := results.Error(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	summary := &UserImportSummary{}
+	var mu sync.Mutex
+	var writeErr error
+	record := func(result *UserImportResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		summary.Results = append(summary.Results, result)
+		switch result.Outcome {
+		case ImportCreated:
+			summary.Created++
+		case ImportUpdated:
+			summary.Updated++
+		case ImportSkipped:
+			summary.Skipped++
+		case ImportFailed:
+			summary.Failed++
+		}
+
+		if results != nil && writeErr == nil {
+			// flushed per row so an interrupted run can be resumed
+			writeErr = results.Write([]string{
+				strconv.Itoa(result.Row), result.Key, string(result.Outcome),
+				strconv.Itoa(result.UserID), result.Error,
+			})
+			if writeErr == nil {
+				results.Flush()
+				writeErr = results.Error()
+			}
+		}
+	}
+
+	type row struct {
+		number int
+		key    string
+		cells  []string
+	}
+	// each worker has its own queue and rows are queued by key, so two
+	// rows for the same user are never imported at once
+	queues := make([]chan row, concurrency)
+	client := c.WithContext(ctx)
+
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan row)
+		wg.Add(1)
+		go func(rows <-chan row) {
+			defer wg.Done()
+			for row := range rows {
+				if err := client.waitForRateLimit(ctx, DefaultRateLimitHeadroom); err != nil {
+					record(&UserImportResult{Row: row.number, Key: row.key, Outcome: ImportFailed, Error: err.Error()})
+					continue
+				}
+				record(client.importUserRow(row.number, row.cells, mapper, matchBy, config.CreateOnly))
+			}
+		}(queues[i])
+	}
+
+	var readErr error
+	for number := 1; ; number++ {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			record(&UserImportResult{Row: number, Outcome: ImportFailed, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+		if done[number] {
+			summary.Resumed++
+			continue
+		}
+
+		key := mapper.key(cells)
+		select {
+		case queues[importQueue(key, concurrency)] <- row{number, key, cells}:
+			continue
+		case <-ctx.Done():
+			readErr = ctx.Err()
+		}
+		break
+	}
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	if readErr != nil {
+		return summary, readErr
+	}
+	return summary, writeErr
+}
+
+func (c *Client) importUserRow(number int, cells []string, mapper *userRowMapper, matchBy string, createOnly bool) *UserImportResult {
+	result := &UserImportResult{Row: number}
+	fail := func(err error) *UserImportResult {
+		result.Outcome = ImportFailed
+		result.Error = err.Error()
+		var reqErr ErrRequestFailed
+		if errors.As(err, &reqErr) {
+			result.Error = fmt.Sprintf("status %d: %s", reqErr.StatusCode, strings.TrimSpace(string(reqErr.Body)))
+		}
+		return result
+	}
+
+	user, key, err := mapper.user(cells)
+	result.Key = key
+	if err != nil {
+		return fail(err)
+	}
+	if key == "" {
+		return fail(ErrMissingField{matchBy})
+	}
+
+	existing, err := c.findImportUser(matchBy, key)
+	if err != nil {
+		return fail(err)
+	}
+
+	if existing == nil {
+		created, err := c.CreateUser(user)
+		if err != nil {
+			return fail(err)
+		}
+		result.Outcome = ImportCreated
+		result.UserID = created.ID
+		return result
+	}
+
+	result.UserID = existing.ID
+	diff, err := diffFields(user, existing, userPlanIgnored)
+	if err != nil {
+		return fail(err)
+	}
+	if createOnly || (len(diff) == 0 && user.Password == "") {
+		result.Outcome = ImportSkipped
+		return result
+	}
+
+	user.ID = existing.ID
+	if _, err := c.UpdateUser(user); err != nil {
+		return fail(err)
+	}
+	result.Outcome = ImportUpdated
+	return result
+}
+
+// importQueue picks the worker queue of a key.  Keys are matched case
+// insensitively, so they are hashed in lower case.
+func importQueue(key string, queues int) int {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(key)))
+	return int(h.Sum32() % uint32(queues))
+}
+
+// findImportUser returns the user whose match field equals key exactly,
+// or nil when there is none
+func (c *Client) findImportUser(matchBy, key string) (*User, error) {
+	query := &UserQuery{}
+	switch matchBy {
+	case "username":
+		query.Username = key
+	case "email":
+		query.Email = key
+	case "external_id":
+		query.ExternalID = key
+	}
+
+	users, err := c.ListUsers(query)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		var value string
+		switch matchBy {
+		case "username":
+			value = user.UserName
+		case "email":
+			value = user.Email
+		case "external_id":
+			value = user.ExternalID
+		}
+		if strings.EqualFold(value, key) {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// readCompletedRows returns the rows a results file records as done
+func readCompletedRows(path string) (map[int]bool, error) {
+	done := map[int]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	for _, record := range records {
+		if len(record) < 3 {
+			continue
+		}
+		row, err := strconv.Atoi(record[0])
+		if err != nil {
+			// the header
+			continue
+		}
+		done[row] = ImportOutcome(record[2]) != ImportFailed
+	}
+	return done, nil
+}
+
+// userRowMapper converts CSV rows to users
+type userRowMapper struct {
+	// fields holds the user field of each column, empty when unmapped
+	fields   []string
+	kinds    map[string]reflect.Kind
+	matchCol int
+}
+
+func newUserRowMapper(header []string, columns map[string]string, matchBy string) (*userRowMapper, error) {
+	kinds := userFieldKinds()
+	mapper := &userRowMapper{fields: make([]string, len(header)), kinds: kinds, matchCol: -1}
+
+	found := map[string]bool{}
+	for i, name := range header {
+		field, ok := columns[strings.TrimSpace(name)]
+		if !ok {
+			continue
+		}
+		found[strings.TrimSpace(name)] = true
+		if _, ok := kinds[field]; !ok && !strings.HasPrefix(field, "custom_attributes.") {
+			return nil, fmt.Errorf("column %q maps to unknown user field %q", name, field)
+		}
+		mapper.fields[i] = field
+		if field == matchBy {
+			mapper.matchCol = i
+		}
+	}
+	for name := range columns {
+		if !found[name] {
+			return nil, fmt.Errorf("column %q is not in the CSV header", name)
+		}
+	}
+	if mapper.matchCol < 0 {
+		return nil, fmt.Errorf("no column maps to %s, which users are matched by", matchBy)
+	}
+	return mapper, nil
+}
+
+// key returns the value a row is matched on
+func (m *userRowMapper) key(cells []string) string {
+	if m.matchCol >= len(cells) {
+		return ""
+	}
+	return strings.TrimSpace(cells[m.matchCol])
+}
+
+// user converts a row, returning the user and the value matched on
+func (m *userRowMapper) user(cells []string) (*User, string, error) {
+	fields := map[string]interface{}{}
+	custom := map[string]interface{}{}
+	key := m.key(cells)
+
+	for i, field := range m.fields {
+		if field == "" || i >= len(cells) {
+			continue
+		}
+		value := strings.TrimSpace(cells[i])
+		if value == "" {
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(field, "custom_attributes."); ok {
+			custom[name] = value
+			continue
+		}
+
+		switch m.kinds[field] {
+		case reflect.Int:
+			// enums such as state and status also accept their names
+			if i, err := strconv.Atoi(value); err == nil {
+				fields[field] = i
+			} else {
+				fields[field] = value
+			}
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, key, fmt.Errorf("%s: invalid boolean %q", field, value)
+			}
+			fields[field] = b
+		case reflect.Slice:
+			var ints []int
+			for _, part := range strings.Split(value, ",") {
+				i, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil {
+					return nil, key, fmt.Errorf("%s: invalid id %q", field, part)
+				}
+				ints = append(ints, i)
+			}
+			fields[field] = ints
+		default:
+			fields[field] = value
+		}
+	}
+	if len(custom) > 0 {
+		fields["custom_attributes"] = custom
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, key, err
+	}
+	var user User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, key, err
+	}
+	return &user, key, nil
+}
+
+// userFieldKinds returns the kind of each User field by its API name
+func userFieldKinds() map[string]reflect.Kind {
+	kinds := map[string]reflect.Kind{}
+	t := reflect.TypeOf(User{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || name == "custom_attributes" || field.Type.Kind() == reflect.Ptr {
+			continue
+		}
+		kinds[name] = field.Type.Kind()
+	}
+	return kinds
+}
@@ -0,0 +1,177 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testImportColumns = map[string]string{
+	"Login":       "username",
+	"Work Email":  "email",
+	"First":       "firstname",
+	"State":       "state",
+	"Group":       "group_id",
+	"Cost Center": "custom_attributes.cost_center",
+}
+
+func TestUserRowMapper(t *testing.T) {
+	mapper, err := newUserRowMapper(
+		[]string{"Login", "Work Email", "First", "State", "Group", "Cost Center", "Ignored"},
+		testImportColumns, "username")
+	require.NoError(t, err)
+
+	user, key, err := mapper.user([]string{" alice ", "alice@example.com", "Alice", "approved", "12", "CC-1", "x"})
+	require.NoError(t, err)
+	require.Equal(t, "alice", key)
+	require.Equal(t, &User{
+		UserName:         "alice",
+		Email:            "alice@example.com",
+		FirstName:        "Alice",
+		State:            UserStateApproved,
+		GroupID:          12,
+		CustomAttributes: map[string]interface{}{"cost_center": "CC-1"},
+	}, user)
+
+	// empty cells are left unset
+	user, _, err = mapper.user([]string{"bob", "", "", "", "", ""})
+	require.NoError(t, err)
+	require.Equal(t, &User{UserName: "bob"}, user)
+
+	_, _, err = mapper.user([]string{"carol", "", "", "bogus", "", ""})
+	require.Error(t, err)
+}
+
+func TestUserRowMapper_invalid(t *testing.T) {
+	_, err := newUserRowMapper([]string{"Login"}, map[string]string{"Login": "nickname"}, "username")
+	require.ErrorContains(t, err, `unknown user field "nickname"`)
+
+	_, err = newUserRowMapper([]string{"Login"}, map[string]string{"Login": "username", "Email": "email"}, "username")
+	require.ErrorContains(t, err, `column "Email" is not in the CSV header`)
+
+	_, err = newUserRowMapper([]string{"Login"}, map[string]string{"Login": "username"}, "email")
+	require.ErrorContains(t, err, "no column maps to email")
+}
+
+func TestLoadUserImportConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+columns:
+  Login: username
+  Work Email: email
+match_by: email
+concurrency: 8
+`), 0o600))
+
+	config, err := LoadUserImportConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, &UserImportConfig{
+		Columns:     map[string]string{"Login": "username", "Work Email": "email"},
+		MatchBy:     "email",
+		Concurrency: 8,
+	}, config)
+}
+
+func TestImportUsers_resume(t *testing.T) {
+	results := filepath.Join(t.TempDir(), "results.csv")
+	require.NoError(t, os.WriteFile(results, []byte(
+		"row,key,outcome,user_id,error\n"+
+			"1,alice,created,10,\n"+
+			"2,bob,failed,0,status 422: invalid\n"), 0o600))
+
+	done, err := readCompletedRows(results)
+	require.NoError(t, err)
+	require.Equal(t, map[int]bool{1: true, 2: false}, done)
+
+	// rows without a username fail before any request is made
+	c := &Client{}
+	csv := "Login,Work Email\nalice,alice@example.com\n,bob@example.com\n"
+	summary, err := c.ImportUsers(context.Background(), strings.NewReader(csv), &UserImportConfig{
+		Columns:     map[string]string{"Login": "username", "Work Email": "email"},
+		ResultsPath: results,
+		Resume:      true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.Resumed)
+	require.Equal(t, 1, summary.Failed)
+	require.Equal(t, []*UserImportResult{{Row: 2, Outcome: ImportFailed, Error: "missing field: username"}}, summary.Results)
+
+	data, err := os.ReadFile(results)
+	require.NoError(t, err)
+	require.Equal(t, ""+
+		"row,key,outcome,user_id,error\n"+
+		"1,alice,created,10,\n"+
+		"2,bob,failed,0,status 422: invalid\n"+
+		"2,,failed,0,missing field: username\n", string(data))
+}
+
+func TestImportUsers_invalid_config(t *testing.T) {
+	c := &Client{}
+	_, err := c.ImportUsers(context.Background(), strings.NewReader("Login\n"), &UserImportConfig{MatchBy: "phone"})
+	require.ErrorContains(t, err, `cannot match users by "phone"`)
+}
+
+// newImportTestClient serves ListUsers, CreateUser and UpdateUser from
+// users, counting the creates and updates
+func newImportTestClient(users map[string]*User) (*Client, *int, *int) {
+	var mu sync.Mutex
+	var creates, updates int
+	return newTestClient(ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body User
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&body)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			matches := []*User{}
+			if user, ok := users[strings.ToLower(r.URL.Query().Get("username"))]; ok {
+				matches = append(matches, user)
+			}
+			json.NewEncoder(w).Encode(matches)
+		case http.MethodPost:
+			creates++
+			body.ID = len(users) + 1
+			users[strings.ToLower(body.UserName)] = &body
+			json.NewEncoder(w).Encode(body)
+		case http.MethodPut:
+			updates++
+			json.NewEncoder(w).Encode(body)
+		}
+	}), &creates, &updates
+}
+
+func TestImportUsers_same_key(t *testing.T) {
+	c, creates, _ := newImportTestClient(map[string]*User{})
+	csv := "Login,Work Email\nalice,alice@example.com\nalice,alice@example.com\nbob,bob@example.com\n"
+	summary, err := c.ImportUsers(context.Background(), strings.NewReader(csv), &UserImportConfig{
+		Columns:     map[string]string{"Login": "username", "Work Email": "email"},
+		Concurrency: 4,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, *creates)
+	require.Equal(t, 2, summary.Created)
+	require.Equal(t, 1, summary.Skipped)
+}
+
+func TestImportUsers_password_only(t *testing.T) {
+	c, _, updates := newImportTestClient(map[string]*User{
+		"alice": {ID: 1, UserName: "alice", Email: "alice@example.com"},
+	})
+	csv := "Login,Work Email,Password\nalice,alice@example.com,hunter2\n"
+	summary, err := c.ImportUsers(context.Background(), strings.NewReader(csv), &UserImportConfig{
+		Columns: map[string]string{"Login": "username", "Work Email": "email", "Password": "password"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, *updates)
+	require.Equal(t, 1, summary.Updated)
+}
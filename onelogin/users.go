@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -25,11 +26,12 @@ type User struct {
 	GroupID              int                    `json:"group_id,omitempty"`
 	RoleIDs              []int                  `json:"role_ids,omitempty"`
 	Phone                string                 `json:"phone,omitempty"`
-	State                int                    `json:"state,omitempty"`
-	Status               int                    `json:"status,omitempty"`
+	State                UserState              `json:"state,omitempty"`
+	Status               UserStatus             `json:"status,omitempty"`
 	DirectoryID          int                    `json:"directory_id,omitempty"`
 	TrustedIDPID         int                    `json:"trusted_idp_id,omitempty"`
 	ManagerADID          int                    `json:"manager_ad_id,omitempty"`
+	ManagerUserID        int                    `json:"manager_user_id,omitempty"`
 	Samaccountname       string                 `json:"samaccountname,omitempty"`
 	MemberOf             string                 `json:"member_of,omitempty"`
 	UserPrincipalName    string                 `json:"userprincipalname,omitempty"`
@@ -37,9 +39,119 @@ type User struct {
 	ExternalID           string                 `json:"external_id,omitempty"`
 	OpenidName           string                 `json:"openid_name,omitempty"`
 	InvalidLoginAttempts int                    `json:"invalid_login_attempts,omitempty"`
+	PreferredLocaleCode  string                 `json:"preferred_locale_code,omitempty"`
 	CustomAttributes     map[string]interface{} `json:"custom_attributes,omitempty"`
+
+	// Read only timestamps, nil when the API returns null
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+	ActivatedAt       *time.Time `json:"activated_at,omitempty"`
+	LastLogin         *time.Time `json:"last_login,omitempty"`
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+	InvitationSentAt  *time.Time `json:"invitation_sent_at,omitempty"`
+}
+
+// DirectoryMastered reports whether the user is synced from a directory.
+// Which fields the sync overwrites depends on the directory's attribute
+// mappings, which the API does not expose.
+func (u *User) DirectoryMastered() bool {
+	return u.DirectoryID != 0
+}
+
+// UserState is the approval state of a user
+// https://developers.onelogin.com/api-docs/2/users/user-resource
+type UserState int
+
+const (
+	UserStateUnapproved UserState = iota
+	UserStateApproved
+	UserStateRejected
+	UserStateUnlicensed
+)
+
+var userStateNames = map[UserState]string{
+	UserStateUnapproved: "unapproved",
+	UserStateApproved:   "approved",
+	UserStateRejected:   "rejected",
+	UserStateUnlicensed: "unlicensed",
+}
+
+func (s UserState) String() string {
+	if name, ok := userStateNames[s]; ok {
+		return name
+	}
+	return "UserState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// UnmarshalJSON accepts either the numeric value used by the API
+// or the name returned by String
+func (s *UserState) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, "user state", userStateNames)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// ParseUserState converts a name returned by String back to a UserState
+func ParseUserState(name string) (UserState, error) {
+	return parseEnum(name, "user state", userStateNames)
 }
 
+// UserStatus is the account status of a user
+// https://developers.onelogin.com/api-docs/2/users/user-resource
+type UserStatus int
+
+const (
+	UserStatusUnactivated UserStatus = iota
+	UserStatusActive
+	UserStatusSuspended
+	UserStatusLocked
+	UserStatusPasswordExpired
+	UserStatusAwaitingPasswordReset
+	_ // 6 is not used by the API
+	UserStatusPasswordPending
+	UserStatusSecurityQuestionsRequired
+)
+
+var userStatusNames = map[UserStatus]string{
+	UserStatusUnactivated:               "unactivated",
+	UserStatusActive:                    "active",
+	UserStatusSuspended:                 "suspended",
+	UserStatusLocked:                    "locked",
+	UserStatusPasswordExpired:           "password_expired",
+	UserStatusAwaitingPasswordReset:     "awaiting_password_reset",
+	UserStatusPasswordPending:           "password_pending",
+	UserStatusSecurityQuestionsRequired: "security_questions_required",
+}
+
+func (s UserStatus) String() string {
+	if name, ok := userStatusNames[s]; ok {
+		return name
+	}
+	return "UserStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// UnmarshalJSON accepts either the numeric value used by the API
+// or the name returned by String
+func (s *UserStatus) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, "user status", userStatusNames)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// ParseUserStatus converts a name returned by String back to a UserStatus
+func ParseUserStatus(name string) (UserStatus, error) {
+	return parseEnum(name, "user status", userStatusNames)
+}
+
+// UserQuery holds the filters supported by ListUsers.  State and Status
+// are pointers since the zero value of each is a valid filter.
 type UserQuery struct {
 	Paging
 	CreatedSince     time.Time              `json:"created_since,omitempty"`
@@ -57,6 +169,8 @@ type UserQuery struct {
 	ExternalID       string                 `json:"external_id,omitempty"`
 	AppID            string                 `json:"app_id,omitempty"`
 	UserIDs          []int                  `json:"user_ids,omitempty"`
+	State            *UserState             `json:"state,omitempty"`
+	Status           *UserStatus            `json:"status,omitempty"`
 	CustomAttributes map[string]interface{} `json:"custom_attributes,omitempty"`
 	Fields           []string               `json:"fields,omitempty"`
 }
@@ -200,6 +314,12 @@ func userQueryToParams(query *UserQuery) map[string]string {
 	if len(query.UserIDs) > 0 {
 		params["user_ids"] = intSliceToString(query.UserIDs, ",")
 	}
+	if query.State != nil {
+		params["state"] = strconv.Itoa(int(*query.State))
+	}
+	if query.Status != nil {
+		params["status"] = strconv.Itoa(int(*query.Status))
+	}
 	if len(query.CustomAttributes) > 0 {
 		for key, value := range query.CustomAttributes {
 			params[key] = value.(string)
@@ -1,6 +1,12 @@
 package onelogin
 
-import "time"
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
 
 func (s *OneLoginTestSuite) Test_ListUsers_success() {
 	users, err := s.client.ListUsers(&UserQuery{
@@ -87,3 +93,77 @@ func (s *OneLoginTestSuite) Test_UpdateUser_missing_fields() {
 	s.Require().NotNil(err)
 	s.Equal(err, ErrMissingField{"id"})
 }
+
+func TestUserStatus_JSON(t *testing.T) {
+	var user User
+	err := json.Unmarshal([]byte(`{"state": 1, "status": 3}`), &user)
+	require.NoError(t, err)
+	require.Equal(t, UserStateApproved, user.State)
+	require.Equal(t, UserStatusLocked, user.Status)
+	require.Equal(t, "approved", user.State.String())
+	require.Equal(t, "locked", user.Status.String())
+
+	err = json.Unmarshal([]byte(`{"state": "unlicensed", "status": "password_pending"}`), &user)
+	require.NoError(t, err)
+	require.Equal(t, UserStateUnlicensed, user.State)
+	require.Equal(t, UserStatusPasswordPending, user.Status)
+
+	body, err := json.Marshal(&User{State: UserStateRejected, Status: UserStatusSuspended})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"state": 2, "status": 2}`, string(body))
+
+	err = json.Unmarshal([]byte(`{"status": "bogus"}`), &user)
+	require.Error(t, err)
+	require.Equal(t, "UserStatus(42)", UserStatus(42).String())
+}
+
+func TestUserQueryToParams_state_status(t *testing.T) {
+	params := userQueryToParams(&UserQuery{})
+	require.NotContains(t, params, "state")
+	require.NotContains(t, params, "status")
+
+	state := UserStateUnapproved
+	status := UserStatusLocked
+	params = userQueryToParams(&UserQuery{State: &state, Status: &status})
+	require.Equal(t, "0", params["state"])
+	require.Equal(t, "3", params["status"])
+
+	status, err := ParseUserStatus("awaiting_password_reset")
+	require.NoError(t, err)
+	require.Equal(t, UserStatusAwaitingPasswordReset, status)
+}
+
+func TestUser_timestamps(t *testing.T) {
+	var user User
+	err := json.Unmarshal([]byte(`{
+		"id": 1,
+		"created_at": "2023-01-02T03:04:05.678Z",
+		"updated_at": "2023-02-02T03:04:05Z",
+		"activated_at": "2023-01-03T00:00:00Z",
+		"last_login": null,
+		"password_changed_at": "2023-01-04T00:00:00Z",
+		"locked_until": null,
+		"invitation_sent_at": "2023-01-02T03:05:00Z",
+		"manager_user_id": 42
+	}`), &user)
+	require.NoError(t, err)
+	require.NotNil(t, user.CreatedAt)
+	require.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 678000000, time.UTC), user.CreatedAt.UTC())
+	require.NotNil(t, user.UpdatedAt)
+	require.NotNil(t, user.ActivatedAt)
+	require.Nil(t, user.LastLogin)
+	require.NotNil(t, user.PasswordChangedAt)
+	require.Nil(t, user.LockedUntil)
+	require.NotNil(t, user.InvitationSentAt)
+	require.Equal(t, 42, user.ManagerUserID)
+
+	// unset timestamps must not be sent on create/update
+	body, err := json.Marshal(&User{UserName: "test"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"username": "test"}`, string(body))
+}
+
+func TestUser_DirectoryMastered(t *testing.T) {
+	require.False(t, (&User{}).DirectoryMastered())
+	require.True(t, (&User{DirectoryID: 1}).DirectoryMastered())
+}
@@ -0,0 +1,291 @@
+package onelogin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const (
+	DefaultWebhookAuthHeader = "Authorization"
+
+	// DefaultWebhookDedupeSize is the number of delivered events
+	// remembered to drop redeliveries
+	DefaultWebhookDedupeSize = 10000
+
+	// DefaultWebhookMaxBodySize is the largest payload accepted, in bytes
+	DefaultWebhookMaxBodySize = 10 << 20
+)
+
+// EventHandlerFunc handles a single event received by a WebhookHandler
+type EventHandlerFunc func(ctx context.Context, event *Event) error
+
+// WebhookConfig configures a WebhookHandler
+type WebhookConfig struct {
+	// AuthHeader is the name of the custom header configured on the
+	// event broadcaster, defaults to DefaultWebhookAuthHeader
+	AuthHeader string
+
+	// AuthValue is the expected value of AuthHeader.  Requests without
+	// it are rejected, an empty value disables the check.
+	AuthValue string
+
+	// DedupeSize is the number of handled events remembered so that
+	// redelivered events are not handled twice, defaults to
+	// DefaultWebhookDedupeSize
+	DedupeSize int
+
+	// MaxBodySize is the largest payload accepted in bytes, larger ones
+	// are rejected with 413.  Defaults to DefaultWebhookMaxBodySize.
+	MaxBodySize int64
+}
+
+// WebhookHandler is an http.Handler receiving payloads from the OneLogin
+// event broadcaster and dispatching each event by type.
+//
+// Payloads may be a JSON array of events (SIEM format) or one JSON
+// object per line.  The handler responds 200 once every event has been
+// handled, and 500 if any handler failed so the broadcaster retries.
+// Events already handled are remembered by uuid and skipped when the
+// payload is redelivered.  A redelivery of an event that is still being
+// handled waits for the outcome, and handles the event itself if the
+// first attempt failed.
+//
+// https://developers.onelogin.com/api-docs/1/events/webhooks
+type WebhookHandler struct {
+	config WebhookConfig
+
+	mu             sync.RWMutex
+	handlers       map[EventTypeID]EventHandlerFunc
+	defaultHandler EventHandlerFunc
+
+	// handled holds the events whose handler succeeded, oldest first in
+	// order.  inProgress holds the events being handled, their channel
+	// is closed once the handler returns.
+	dedupeMu   sync.Mutex
+	handled    map[string]struct{}
+	order      []string
+	inProgress map[string]chan struct{}
+}
+
+func NewWebhookHandler(config WebhookConfig) *WebhookHandler {
+	if config.AuthHeader == "" {
+		config.AuthHeader = DefaultWebhookAuthHeader
+	}
+	if config.DedupeSize == 0 {
+		config.DedupeSize = DefaultWebhookDedupeSize
+	}
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = DefaultWebhookMaxBodySize
+	}
+
+	return &WebhookHandler{
+		config:     config,
+		handlers:   map[EventTypeID]EventHandlerFunc{},
+		handled:    map[string]struct{}{},
+		inProgress: map[string]chan struct{}{},
+	}
+}
+
+// Handle registers the handler for an event type, replacing any
+// handler previously registered for it
+func (h *WebhookHandler) Handle(eventType EventTypeID, handler EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = handler
+}
+
+// HandleDefault registers the handler for event types without their
+// own handler.  Events with no handler are acknowledged and dropped.
+func (h *WebhookHandler) HandleDefault(handler EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.defaultHandler = handler
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.config.AuthValue != "" {
+		got := r.Header.Get(h.config.AuthHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(h.config.AuthValue)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	events, err := decodeWebhookEvents(http.MaxBytesReader(w, r.Body, h.config.MaxBodySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		// retrying a malformed payload will not help, so reject it
+		// without asking for redelivery
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range events {
+		key := webhookEventKey(event)
+		if key != "" {
+			claimed, err := h.claim(r.Context(), key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			if !claimed {
+				continue
+			}
+		}
+
+		if err := h.handle(r.Context(), key, event); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handle runs the handler of an event and finishes its claim, the event
+// is not remembered if the handler fails or panics
+func (h *WebhookHandler) handle(ctx context.Context, key string, event *Event) error {
+	handled := false
+	if key != "" {
+		defer func() { h.finish(key, handled) }()
+	}
+
+	var err error
+	if handler := h.handlerFor(event.EventTypeID); handler != nil {
+		err = handler(ctx, event)
+	}
+	handled = err == nil
+	return err
+}
+
+func (h *WebhookHandler) handlerFor(eventType EventTypeID) EventHandlerFunc {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if handler, ok := h.handlers[eventType]; ok {
+		return handler
+	}
+	return h.defaultHandler
+}
+
+// claim marks an event as in progress, it returns false if the event was
+// already handled.  If the event is in progress claim waits for the
+// outcome, so that a redelivery is not acknowledged before the event is.
+func (h *WebhookHandler) claim(ctx context.Context, key string) (bool, error) {
+	for {
+		h.dedupeMu.Lock()
+		if _, ok := h.handled[key]; ok {
+			h.dedupeMu.Unlock()
+			return false, nil
+		}
+		done, ok := h.inProgress[key]
+		if !ok {
+			h.inProgress[key] = make(chan struct{})
+			h.dedupeMu.Unlock()
+			return true, nil
+		}
+		h.dedupeMu.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// finish ends the handling of a claimed event, remembering it if its
+// handler succeeded
+func (h *WebhookHandler) finish(key string, handled bool) {
+	h.dedupeMu.Lock()
+	defer h.dedupeMu.Unlock()
+
+	close(h.inProgress[key])
+	delete(h.inProgress, key)
+	if !handled {
+		return
+	}
+
+	h.handled[key] = struct{}{}
+	h.order = append(h.order, key)
+	if len(h.order) > h.config.DedupeSize {
+		delete(h.handled, h.order[0])
+		h.order = h.order[1:]
+	}
+}
+
+// webhookEventKey identifies an event for deduplication, broadcaster
+// events carry a uuid while events from the API only have an id
+func webhookEventKey(event *Event) string {
+	if event.UUID != "" {
+		return event.UUID
+	}
+	if event.ID != 0 {
+		return strconv.Itoa(event.ID)
+	}
+	return ""
+}
+
+// decodeWebhookEvents reads either a JSON array of events or a stream
+// of JSON objects
+func decodeWebhookEvents(body io.Reader) ([]*Event, error) {
+	reader := bufio.NewReader(body)
+	first, err := peekNonSpace(reader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	if first == '[' {
+		err = json.NewDecoder(reader).Decode(&events)
+		return events, err
+	}
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var event Event
+		err := decoder.Decode(&event)
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := reader.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
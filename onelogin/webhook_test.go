@@ -0,0 +1,140 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func postWebhook(h http.Handler, auth, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/onelogin", strings.NewReader(body))
+	if auth != "" {
+		req.Header.Set("X-Onelogin-Auth", auth)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookHandler(t *testing.T) {
+	h := NewWebhookHandler(WebhookConfig{AuthHeader: "X-Onelogin-Auth", AuthValue: "secret"})
+
+	var logins, other []string
+	failNext := true
+	h.Handle(5, func(ctx context.Context, e *Event) error {
+		logins = append(logins, e.UUID)
+		return nil
+	})
+	h.HandleDefault(func(ctx context.Context, e *Event) error {
+		if failNext {
+			failNext = false
+			return errors.New("temporary failure")
+		}
+		other = append(other, e.UUID)
+		return nil
+	})
+
+	payload := `[
+		{"uuid": "a", "event_type_id": 5, "user_id": 1},
+		{"uuid": "b", "event_type_id": 13, "user_id": 2}
+	]`
+
+	rec := postWebhook(h, "", payload)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Empty(t, logins)
+
+	// second event fails, the broadcaster is asked to redeliver
+	rec = postWebhook(h, "secret", payload)
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, []string{"a"}, logins)
+
+	// on redelivery the already handled event is skipped
+	rec = postWebhook(h, "secret", payload)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{"a"}, logins)
+	require.Equal(t, []string{"b"}, other)
+
+	// newline delimited payloads
+	rec = postWebhook(h, "secret", "{\"uuid\": \"c\", \"event_type_id\": 5}\n{\"uuid\": \"d\", \"event_type_id\": 5}\n")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{"a", "c", "d"}, logins)
+
+	rec = postWebhook(h, "secret", "{not json")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWebhookHandler_dedupe_size(t *testing.T) {
+	h := NewWebhookHandler(WebhookConfig{DedupeSize: 1})
+	count := 0
+	h.HandleDefault(func(ctx context.Context, e *Event) error {
+		count++
+		return nil
+	})
+
+	postWebhook(h, "", `{"uuid": "a"}`)
+	postWebhook(h, "", `{"uuid": "b"}`)
+	postWebhook(h, "", `{"uuid": "a"}`)
+	require.Equal(t, 3, count)
+}
+
+func TestWebhookHandler_concurrent_redelivery(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		h := NewWebhookHandler(WebhookConfig{})
+		started := make(chan struct{})
+		release := make(chan struct{})
+		count := 0
+		h.HandleDefault(func(ctx context.Context, e *Event) error {
+			count++
+			if count == 1 {
+				close(started)
+				<-release
+				if fail {
+					return errors.New("temporary failure")
+				}
+			}
+			return nil
+		})
+
+		first := make(chan int)
+		go func() {
+			first <- postWebhook(h, "", `{"uuid": "a"}`).Code
+		}()
+		<-started
+
+		// the redelivery arrives while the first delivery is being
+		// handled and waits for its outcome
+		second := make(chan int)
+		go func() {
+			second <- postWebhook(h, "", `{"uuid": "a"}`).Code
+		}()
+		select {
+		case <-second:
+			t.Fatal("redelivery acknowledged before the event was handled")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(release)
+
+		if fail {
+			// the redelivery handles the event the first attempt failed
+			require.Equal(t, http.StatusInternalServerError, <-first)
+			require.Equal(t, http.StatusOK, <-second)
+			require.Equal(t, 2, count)
+		} else {
+			require.Equal(t, http.StatusOK, <-first)
+			require.Equal(t, http.StatusOK, <-second)
+			require.Equal(t, 1, count)
+		}
+	}
+}
+
+func TestWebhookHandler_max_body_size(t *testing.T) {
+	h := NewWebhookHandler(WebhookConfig{MaxBodySize: 16})
+	rec := postWebhook(h, "", `[{"uuid": "a"}, {"uuid": "b"}]`)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}